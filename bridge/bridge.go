@@ -0,0 +1,211 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Package bridge forwards gitee webhook events to chat robots: DingTalk (钉钉),
+// Feishu/Lark (飞书) and WeCom (企业微信). Events are rendered to markdown with
+// configurable templates and sent through each platform's custom robot webhook.
+//
+//  handler := gitee.NewHookHandler([]byte("hook secret"))
+//  b := bridge.New(bridge.NewRenderer(), &bridge.DingTalk{Webhook: "...", Secret: "SEC..."})
+//  b.Register(handler)
+//  http.Handle("/gitee", handler)
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+)
+
+// Message is a rendered event, ready to be sent to a robot.
+type Message struct {
+	Title string // 标题, 钉钉和飞书卡片会用到
+	Text  string // markdown 正文
+}
+
+// Robot sends messages to a chat group.
+type Robot interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Bridge renders webhook events and sends them to every robot.
+type Bridge struct {
+	Renderer *Renderer
+	Robots   []Robot
+
+	// ErrorLog logs the robots Forward failed to send to. If nil, the log
+	// package's standard logger is used.
+	ErrorLog *log.Logger
+}
+
+// New returns a Bridge rendering events with r and sending them to robots.
+func New(r *Renderer, robots ...Robot) *Bridge {
+	return &Bridge{Renderer: r, Robots: robots}
+}
+
+// Register makes h forward every event type the renderer has a template for.
+func (b *Bridge) Register(h *gitee.HookHandler) {
+	for _, eventType := range b.Renderer.EventTypes() {
+		h.On(eventType, b.Forward)
+	}
+}
+
+// Forward renders event and sends it to all robots. Events without a template
+// are dropped, events whose template fails are sent as plain text.
+//
+// Robots that fail are logged to ErrorLog and Forward still returns nil, so
+// the delivery is acknowledged: if it failed, gitee would redeliver the event
+// and the robots that already succeeded would post the message again.
+func (b *Bridge) Forward(ctx context.Context, event interface{}) error {
+	msg, err := b.Renderer.Render(event)
+	if err != nil {
+		msg = plainMessage(event, err)
+	}
+	if msg == nil {
+		return nil
+	}
+
+	if err := b.Send(ctx, msg); err != nil {
+		if b.ErrorLog != nil {
+			b.ErrorLog.Printf("bridge: %v: %v", eventTypeOf(event), err)
+		} else {
+			log.Printf("bridge: %v: %v", eventTypeOf(event), err)
+		}
+	}
+	return nil
+}
+
+// Send sends msg to all robots. Every robot is tried, the robots that failed
+// are reported in a *SendError.
+func (b *Bridge) Send(ctx context.Context, msg *Message) error {
+	errs := make(map[int]error)
+	for i, robot := range b.Robots {
+		if err := robot.Send(ctx, msg); err != nil {
+			errs[i] = err
+		}
+	}
+	if len(errs) > 0 {
+		return &SendError{Errors: errs}
+	}
+	return nil
+}
+
+// plainMessage is sent instead of the rendered event when its template fails,
+// e.g. on a field of a nil pointer.
+func plainMessage(event interface{}, err error) *Message {
+	eventType := eventTypeOf(event)
+	return &Message{
+		Title: eventType,
+		Text:  fmt.Sprintf("收到 %v, 模板渲染失败: %v", eventType, err),
+	}
+}
+
+// SendError reports the robots that failed to send a message.
+type SendError struct {
+	Errors map[int]error // robot index in Bridge.Robots -> error
+}
+
+// Robots returns the indexes of the failed robots in ascending order.
+func (e *SendError) Robots() []int {
+	robots := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		robots = append(robots, i)
+	}
+	sort.Ints(robots)
+	return robots
+}
+
+func (e *SendError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to send to %d robot(s):", len(e.Errors))
+	for _, i := range e.Robots() {
+		fmt.Fprintf(&b, " robot %d: %v;", i, e.Errors[i])
+	}
+	return strings.TrimSuffix(b.String(), ";")
+}
+
+// Unwrap returns the error of the first failed robot.
+func (e *SendError) Unwrap() error {
+	robots := e.Robots()
+	if len(robots) == 0 {
+		return nil
+	}
+	return e.Errors[robots[0]]
+}
+
+// postJSON posts body to webhook and decodes the JSON response into v.
+func postJSON(ctx context.Context, client *http.Client, webhook string, body, v interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", webhook, bytes.NewReader(buf))
+	if err != nil {
+		return fmt.Errorf("POST %v: invalid webhook URL", redact(webhook))
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	// url.Error 里面带着完整的 webhook, 也就是机器人的 token, 不能直接返回
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		if uerr, ok := err.(*url.Error); ok {
+			err = uerr.Err
+		}
+		return fmt.Errorf("POST %v: %v", redact(webhook), err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("POST %v: %v", redact(webhook), err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("POST %v: %v: %s", redact(webhook), resp.Status, data)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// redact hides the robot token (access_token, sign and timestamp for DingTalk,
+// key for WeCom, the last path segment for Feishu) of a webhook URL in error
+// messages.
+func redact(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "REDACTED"
+	}
+	q := u.Query()
+	for _, k := range []string{"access_token", "key", "sign", "timestamp"} {
+		if q.Get(k) != "" {
+			q.Set(k, "REDACTED")
+		}
+	}
+	u.RawQuery = q.Encode()
+	if i := strings.LastIndex(u.Path, "/hook/"); i >= 0 {
+		u.Path = u.Path[:i] + "/hook/REDACTED"
+	}
+	return u.String()
+}
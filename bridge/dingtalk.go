@@ -0,0 +1,79 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package bridge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DingTalk is a DingTalk (钉钉) custom robot.
+// https://open.dingtalk.com/document/robots/custom-robot-access
+type DingTalk struct {
+	// Webhook is the robot URL, https://oapi.dingtalk.com/robot/send?access_token=xxx
+	Webhook string
+	// Secret is the "加签" secret (SEC...). Leave empty if the robot uses keywords or IP whitelisting.
+	Secret string
+	// Client is the HTTP client used, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// dingTalkSign returns the sign parameter for timestamp (in milliseconds):
+// base64(HMAC-SHA256(secret, timestamp + "\n" + secret)).
+func dingTalkSign(timestamp int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Send implements Robot, sending msg as a markdown message.
+func (d *DingTalk) Send(ctx context.Context, msg *Message) error {
+	webhook := d.Webhook
+	if d.Secret != "" {
+		timestamp := time.Now().UnixNano() / int64(time.Millisecond)
+		sep := "?"
+		if strings.Contains(webhook, "?") {
+			sep = "&"
+		}
+		webhook += fmt.Sprintf("%vtimestamp=%d&sign=%v", sep, timestamp, url.QueryEscape(dingTalkSign(timestamp, d.Secret)))
+	}
+
+	body := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": msg.Title,
+			"text":  "#### " + msg.Title + "\n\n" + msg.Text,
+		},
+	}
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := postJSON(ctx, d.Client, webhook, body, &result); err != nil {
+		return err
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("dingtalk: %d %v", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package bridge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Feishu is a Feishu/Lark (飞书) custom bot.
+// https://open.feishu.cn/document/client-docs/bot-v3/add-custom-bot
+type Feishu struct {
+	// Webhook is the bot URL, https://open.feishu.cn/open-apis/bot/v2/hook/xxx
+	Webhook string
+	// Secret is the "签名校验" secret. Leave empty if signing is not enabled.
+	Secret string
+	// Color is the card header template color, e.g. blue, green, red. Defaults to blue.
+	Color string
+	// Client is the HTTP client used, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// feishuSign returns the sign field for timestamp (in seconds): the
+// base64 HMAC-SHA256 of an empty message keyed with timestamp + "\n" + secret.
+func feishuSign(timestamp int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(strconv.FormatInt(timestamp, 10)+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Send implements Robot, sending msg as an interactive card.
+func (f *Feishu) Send(ctx context.Context, msg *Message) error {
+	color := f.Color
+	if color == "" {
+		color = "blue"
+	}
+
+	body := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"title":    map[string]string{"tag": "plain_text", "content": msg.Title},
+				"template": color,
+			},
+			"elements": []interface{}{
+				map[string]string{"tag": "markdown", "content": msg.Text},
+			},
+		},
+	}
+	if f.Secret != "" {
+		timestamp := time.Now().Unix()
+		body["timestamp"] = strconv.FormatInt(timestamp, 10)
+		body["sign"] = feishuSign(timestamp, f.Secret)
+	}
+
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := postJSON(ctx, f.Client, f.Webhook, body, &result); err != nil {
+		return err
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("feishu: %d %v", result.Code, result.Msg)
+	}
+	return nil
+}
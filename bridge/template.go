@@ -0,0 +1,198 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package bridge
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+)
+
+// defaultTemplates are the title and text templates used by NewRenderer,
+// keyed by X-Gitee-Event. The dot is the payload struct returned by
+// gitee.ParseWebHook.
+var defaultTemplates = map[string][2]string{
+	gitee.PushHookEventType: {
+		`{{with .Repository}}[{{.FullName}}] {{end}}{{.UserName}} 推送了 {{len .Commits}} 个提交到 {{ref .Ref}}`,
+		`**{{.UserName}}** 推送到 {{with .Repository}}[{{.FullName}}]({{.HTMLURL}}) 的 {{end}}**{{ref .Ref}}** 分支
+{{range .Commits}}
+- [{{short .ID}}]({{.URL}}) {{firstLine .Message}}{{with .Author}} - {{.Name}}{{end}}{{end}}
+{{if .Compare}}
+[查看变更]({{.Compare}}){{end}}`,
+	},
+	gitee.TagPushHookEventType: {
+		`{{with .Repository}}[{{.FullName}}] {{end}}{{.UserName}} {{if isTrue .Deleted}}删除{{else}}推送{{end}}了标签 {{ref .Ref}}`,
+		`**{{.UserName}}** 在 {{with .Repository}}[{{.FullName}}]({{.HTMLURL}}) {{end}}{{if isTrue .Deleted}}删除{{else}}推送{{end}}了标签 **{{ref .Ref}}**`,
+	},
+	gitee.IssueHookEventType: {
+		`{{with .Repository}}[{{.FullName}}] {{end}}Issue {{with .Issue}}{{.Number}} {{end}}{{.Action}}{{with .Issue}}: {{.Title}}{{end}}`,
+		`{{with .Sender}}**{{.Name}}** {{end}}{{.Action}} Issue{{with .Issue}} [#{{.Number}} {{.Title}}]({{.HTMLURL}})
+{{if .StateName}}
+> 状态: {{.StateName}}{{end}}{{with .Assignee}}
+> 负责人: {{.Name}}{{end}}{{end}}`,
+	},
+	gitee.NoteHookEventType: {
+		`{{with .Repository}}[{{.FullName}}] {{end}}{{with .Author}}{{.Name}} {{end}}评论了 {{.NoteableType}} {{.Title}}`,
+		`{{with .Author}}**{{.Name}}** {{end}}评论了 {{.NoteableType}} {{with .Comment}}[{{$.Title}}]({{.HTMLURL}})
+
+> {{firstLine .Body}}{{else}}{{.Title}}{{end}}`,
+	},
+	gitee.MergeRequestHookEventType: {
+		`{{with .Repository}}[{{.FullName}}] {{end}}Pull Request {{with .PullRequest}}!{{.Number}} {{end}}{{.Action}}{{with .PullRequest}}: {{.Title}}{{end}}`,
+		`{{with .Sender}}**{{.Name}}** {{end}}{{.Action}} Pull Request{{with .PullRequest}} [!{{.Number}} {{.Title}}]({{.HTMLURL}}){{end}}
+
+> {{.SourceBranch}} → {{.TargetBranch}}`,
+	},
+}
+
+// Template renders the title and markdown text of one event type.
+type Template struct {
+	Title *template.Template
+	Text  *template.Template
+}
+
+// Renderer turns gitee webhook events into messages.
+type Renderer struct {
+	Templates map[string]*Template // 按 X-Gitee-Event 索引
+}
+
+// NewRenderer returns a Renderer with the default (Chinese) templates for all
+// gitee webhook event types.
+func NewRenderer() *Renderer {
+	r := &Renderer{Templates: make(map[string]*Template)}
+	for eventType, t := range defaultTemplates {
+		if err := r.SetTemplate(eventType, t[0], t[1]); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Funcs are the functions available to templates besides the text/template builtins:
+//
+//  ref       "refs/heads/master" -> "master"
+//  short     commit SHA -> first 8 characters
+//  firstLine first line of a (commit) message
+//  isTrue    whether a *bool is set and true, {{if}} alone is true for any non-nil pointer
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"ref": func(ref interface{}) string {
+			s := deref(ref)
+			s = strings.TrimPrefix(s, "refs/heads/")
+			return strings.TrimPrefix(s, "refs/tags/")
+		},
+		"short": func(sha interface{}) string {
+			s := deref(sha)
+			if len(s) > 8 {
+				return s[:8]
+			}
+			return s
+		},
+		"isTrue": func(b interface{}) bool {
+			return deref(b) == "true"
+		},
+		"firstLine": func(msg interface{}) string {
+			s := strings.TrimSpace(deref(msg))
+			if i := strings.IndexByte(s, '\n'); i >= 0 {
+				return s[:i]
+			}
+			return s
+		},
+	}
+}
+
+// SetTemplate parses and sets the title and text templates of eventType.
+// Fields that may be missing from a payload should be guarded with
+// {{if}} or {{with}}, evaluating a field of a nil pointer fails the render.
+func (r *Renderer) SetTemplate(eventType, title, text string) error {
+	tt, err := template.New(eventType + " title").Funcs(Funcs()).Parse(title)
+	if err != nil {
+		return err
+	}
+	xt, err := template.New(eventType + " text").Funcs(Funcs()).Parse(text)
+	if err != nil {
+		return err
+	}
+	r.Templates[eventType] = &Template{Title: tt, Text: xt}
+	return nil
+}
+
+// EventTypes returns the event types the renderer has templates for.
+func (r *Renderer) EventTypes() []string {
+	types := make([]string, 0, len(r.Templates))
+	for eventType := range r.Templates {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Render renders event, a payload struct returned by gitee.ParseWebHook. It
+// returns nil if there is no template for the event type.
+func (r *Renderer) Render(event interface{}) (*Message, error) {
+	eventType := eventTypeOf(event)
+	t, ok := r.Templates[eventType]
+	if !ok {
+		return nil, nil
+	}
+
+	var title, text bytes.Buffer
+	if err := t.Title.Execute(&title, event); err != nil {
+		return nil, err
+	}
+	if err := t.Text.Execute(&text, event); err != nil {
+		return nil, err
+	}
+	return &Message{
+		Title: strings.TrimSpace(title.String()),
+		Text:  strings.TrimSpace(text.String()),
+	}, nil
+}
+
+// eventTypeOf returns the X-Gitee-Event of a payload struct.
+func eventTypeOf(event interface{}) string {
+	switch event.(type) {
+	case *gitee.PushHookEvent:
+		return gitee.PushHookEventType
+	case *gitee.TagPushHookEvent:
+		return gitee.TagPushHookEventType
+	case *gitee.IssueHookEvent:
+		return gitee.IssueHookEventType
+	case *gitee.NoteHookEvent:
+		return gitee.NoteHookEventType
+	case *gitee.MergeRequestHookEvent:
+		return gitee.MergeRequestHookEventType
+	}
+	return ""
+}
+
+func deref(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(rv.Interface())
+}
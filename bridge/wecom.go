@@ -0,0 +1,52 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// WeCom is a WeCom (企业微信) group robot. WeCom robots are authenticated by
+// the key in the webhook URL only, there is no signing.
+// https://developer.work.weixin.qq.com/document/path/91770
+type WeCom struct {
+	// Webhook is the robot URL, https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=xxx
+	Webhook string
+	// Client is the HTTP client used, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// Send implements Robot, sending msg as a markdown message.
+func (w *WeCom) Send(ctx context.Context, msg *Message) error {
+	body := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"content": "### " + msg.Title + "\n" + msg.Text,
+		},
+	}
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := postJSON(ctx, w.Client, w.Webhook, body, &result); err != nil {
+		return err
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("wecom: %d %v", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
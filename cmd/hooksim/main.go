@@ -0,0 +1,178 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Command hooksim sends realistic, correctly signed gitee webhook deliveries
+// to a local URL, so hook receivers can be exercised without pushing to a
+// real repository. 本地开发和离线 CI 里面模拟 gitee 的 WebHook 推送
+//
+// Usage:
+//
+//  hooksim -url http://localhost:8080/hook -event push -secret s3cret -sign
+//  hooksim -url http://localhost:8080/hook -event merge_request -action merge
+//  hooksim -url http://localhost:8080/hook -event note -file my-note.json
+//  hooksim -url http://localhost:8080/hook -all
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// events maps the -event names to the X-Gitee-Event values, in the order
+// -all sends them.
+var events = []struct {
+	name      string
+	eventType string
+}{
+	{"push", gitee.PushHookEventType},
+	{"tag_push", gitee.TagPushHookEventType},
+	{"issue", gitee.IssueHookEventType},
+	{"note", gitee.NoteHookEventType},
+	{"merge_request", gitee.MergeRequestHookEventType},
+}
+
+func main() {
+	var (
+		url     = flag.String("url", "http://localhost:8080/", "URL of the hook receiver")
+		event   = flag.String("event", "push", "event to send: push, tag_push, issue, note or merge_request")
+		all     = flag.Bool("all", false, "send one delivery of every event")
+		file    = flag.String("file", "", "payload file, defaults to the built-in fixture of the event")
+		secret  = flag.String("secret", "", "hook password, or sign key with -sign")
+		sign    = flag.Bool("sign", false, "sign the delivery with -secret instead of sending it as password")
+		ref     = flag.String("ref", "", "override the ref of push and tag_push payloads")
+		action  = flag.String("action", "", "override the action of issue, note and merge_request payloads")
+		timeout = flag.Duration("timeout", 10*time.Second, "timeout of each delivery")
+	)
+	flag.Parse()
+
+	if *all && *file != "" {
+		fmt.Fprintln(os.Stderr, "-file cannot be used with -all: a payload file is of one event type")
+		os.Exit(2)
+	}
+
+	names := []string{*event}
+	if *all {
+		names = names[:0]
+		for _, e := range events {
+			names = append(names, e.name)
+		}
+	}
+
+	encryption := gitee.HookEncryptionPassword
+	if *sign {
+		encryption = gitee.HookEncryptionSign
+	}
+
+	failed := false
+	for _, name := range names {
+		name, eventType, err := lookupEvent(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		payload, err := loadPayload(name, *file)
+		if err == nil {
+			payload, err = override(payload, *ref, *action)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		d := &gitee.HookDelivery{
+			EventType:      eventType,
+			Payload:        payload,
+			Secret:         []byte(*secret),
+			EncryptionType: encryption,
+		}
+		if err := send(*url, d, *timeout); err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", eventType, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// lookupEvent accepts either a -event name or an X-Gitee-Event value.
+func lookupEvent(event string) (name, eventType string, err error) {
+	for _, e := range events {
+		if e.name == event || e.eventType == event {
+			return e.name, e.eventType, nil
+		}
+	}
+	return "", "", fmt.Errorf("unknown event %q", event)
+}
+
+func loadPayload(name, file string) ([]byte, error) {
+	if file != "" {
+		return ioutil.ReadFile(file)
+	}
+	return fixtures.ReadFile("fixtures/" + name + ".json")
+}
+
+// override sets the top level ref and action fields of payload, if given and
+// present in payload. The rest of payload is kept byte for byte.
+func override(payload []byte, ref, action string) ([]byte, error) {
+	if ref == "" && action == "" {
+		return payload, nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	for _, f := range []struct{ key, value string }{{"ref", ref}, {"action", action}} {
+		if _, ok := fields[f.key]; !ok || f.value == "" {
+			continue
+		}
+		var err error
+		if payload, err = gitee.SetPayloadField(payload, f.key, f.value); err != nil {
+			return nil, err
+		}
+	}
+	return payload, nil
+}
+
+func send(url string, d *gitee.HookDelivery, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp, err := gitee.SendHook(ctx, nil, url, d)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	fmt.Printf("%v -> %v %v\n", d.EventType, resp.Status, strings.TrimSpace(string(body)))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("receiver returned %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"fmt"
+)
+
+// EventListOptions specifies the optional parameters to the ActivityService
+// event list methods. gitee 的动态接口不是按页码分页的，而是用 prev_id 和 limit 滚动获取
+type EventListOptions struct {
+	PrevID int64 `url:"prev_id,omitempty"` // 滚动列表的最后一条记录的id
+	Limit  int   `url:"limit,omitempty"`   // 滚动列表每页的数量，最大为 100
+}
+
+// listEvents is the shared implementation of all event listing methods.
+func (s *ActivityService) listEvents(ctx context.Context, u string, opts *EventListOptions) ([]*Event, *Response, error) {
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []*Event
+	resp, err := s.client.Do(ctx, req, &events)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return events, resp, nil
+}
+
+// ListRepositoryEvents lists events for a repository.
+//
+//  列出仓库的所有动态 GET https://gitee.com/api/v5/repos/{owner}/{repo}/events
+func (s *ActivityService) ListRepositoryEvents(ctx context.Context, owner, repo string, opts *EventListOptions) ([]*Event, *Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/events", owner, repo)
+	return s.listEvents(ctx, u, opts)
+}
+
+// ListEventsForRepoNetwork lists public events for a network of repositories.
+//
+//  列出仓库的所有公开动态 GET https://gitee.com/api/v5/networks/{owner}/{repo}/events
+func (s *ActivityService) ListEventsForRepoNetwork(ctx context.Context, owner, repo string, opts *EventListOptions) ([]*Event, *Response, error) {
+	u := fmt.Sprintf("networks/%v/%v/events", owner, repo)
+	return s.listEvents(ctx, u, opts)
+}
+
+// ListEventsForOrganization lists public events for an organization.
+//
+//  列出组织的公开动态 GET https://gitee.com/api/v5/orgs/{org}/events
+func (s *ActivityService) ListEventsForOrganization(ctx context.Context, org string, opts *EventListOptions) ([]*Event, *Response, error) {
+	u := fmt.Sprintf("orgs/%v/events", org)
+	return s.listEvents(ctx, u, opts)
+}
+
+// ListEventsPerformedByUser lists the events performed by a user. If publicOnly is
+// true, only public events will be returned.
+//
+//  列出用户的动态 GET https://gitee.com/api/v5/users/{username}/events
+//  列出用户的公开动态 GET https://gitee.com/api/v5/users/{username}/events/public
+func (s *ActivityService) ListEventsPerformedByUser(ctx context.Context, user string, publicOnly bool, opts *EventListOptions) ([]*Event, *Response, error) {
+	var u string
+	if publicOnly {
+		u = fmt.Sprintf("users/%v/events/public", user)
+	} else {
+		u = fmt.Sprintf("users/%v/events", user)
+	}
+	return s.listEvents(ctx, u, opts)
+}
+
+// ListEventsReceivedByUser lists the events received by a user. If publicOnly is
+// true, only public events will be returned.
+//
+//  列出一个用户收到的动态 GET https://gitee.com/api/v5/users/{username}/received_events
+//  列出一个用户收到的公开动态 GET https://gitee.com/api/v5/users/{username}/received_events/public
+func (s *ActivityService) ListEventsReceivedByUser(ctx context.Context, user string, publicOnly bool, opts *EventListOptions) ([]*Event, *Response, error) {
+	var u string
+	if publicOnly {
+		u = fmt.Sprintf("users/%v/received_events/public", user)
+	} else {
+		u = fmt.Sprintf("users/%v/received_events", user)
+	}
+	return s.listEvents(ctx, u, opts)
+}
+
+// ListUserEventsForOrganization provides the user's organization dashboard.
+// You must be authenticated as the user to view this.
+//
+//  列出用户所属组织的动态 GET https://gitee.com/api/v5/users/{username}/events/orgs/{org}
+func (s *ActivityService) ListUserEventsForOrganization(ctx context.Context, org, user string, opts *EventListOptions) ([]*Event, *Response, error) {
+	u := fmt.Sprintf("users/%v/events/orgs/%v", user, org)
+	return s.listEvents(ctx, u, opts)
+}
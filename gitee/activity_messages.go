@@ -0,0 +1,146 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Message represents a private message (私信) between gitee users.
+type Message struct {
+	ID        *int64     `json:"id,omitempty"`
+	Sender    *User      `json:"sender,omitempty"`
+	Unread    *bool      `json:"unread,omitempty"`
+	Content   *string    `json:"content,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	URL       *string    `json:"url,omitempty"`
+	HTMLURL   *string    `json:"html_url,omitempty"`
+}
+
+func (m Message) String() string {
+	return Stringify(m)
+}
+
+// messageList is the wrapper gitee returns around a list of private messages.
+type messageList struct {
+	TotalCount *int       `json:"total_count,omitempty"`
+	List       []*Message `json:"list,omitempty"`
+}
+
+// MessageListOptions specifies the optional parameters to the
+// ActivityService.ListMessages method.
+type MessageListOptions struct {
+	Unread bool   `url:"unread,omitempty"` // 是否只显示未读私信，默认：否
+	Since  string `url:"since,omitempty"`  // 只获取在给定时间后更新的私信，要求时间格式为 ISO 8601
+	Before string `url:"before,omitempty"` // 只获取在给定时间前更新的私信，要求时间格式为 ISO 8601
+	IDs    string `url:"ids,omitempty"`    // 指定一组私信 ID，以 , 分隔
+
+	ListOptions
+}
+
+// ListMessages lists the private messages of the authenticated user.
+//
+//  列出授权用户的所有私信 GET https://gitee.com/api/v5/notifications/messages
+func (s *ActivityService) ListMessages(ctx context.Context, opts *MessageListOptions) ([]*Message, *Response, error) {
+	u, err := addOptions("notifications/messages", opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages := new(messageList)
+	resp, err := s.client.Do(ctx, req, messages)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return messages.List, resp, nil
+}
+
+// GetMessage gets a single private message.
+//
+//  获取一条私信 GET https://gitee.com/api/v5/notifications/messages/{id}
+func (s *ActivityService) GetMessage(ctx context.Context, id int64) (*Message, *Response, error) {
+	u := fmt.Sprintf("notifications/messages/%v", id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	message := new(Message)
+	resp, err := s.client.Do(ctx, req, message)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return message, resp, nil
+}
+
+type MessageSendRequest struct {
+	Username *string `json:"username"` // 用户名(username/login)
+	Content  *string `json:"content"`  // 私信内容
+}
+
+// SendMessage sends a private message to a user.
+//
+//  发送私信给指定用户 POST https://gitee.com/api/v5/notifications/messages
+func (s *ActivityService) SendMessage(ctx context.Context, msgReq *MessageSendRequest) (*Message, *Response, error) {
+	req, err := s.client.NewRequest("POST", "notifications/messages", msgReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	message := new(Message)
+	resp, err := s.client.Do(ctx, req, message)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return message, resp, nil
+}
+
+// MarkMessageRead marks a single private message as read.
+//
+//  标记一条私信为已读 PATCH https://gitee.com/api/v5/notifications/messages/{id}
+func (s *ActivityService) MarkMessageRead(ctx context.Context, id int64) (*Response, error) {
+	u := fmt.Sprintf("notifications/messages/%v", id)
+
+	req, err := s.client.NewRequest("PATCH", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// MarkMessagesRead marks all private messages, or the ones listed in
+// markReq.IDs, as read.
+//
+//  标记所有私信为已读 PUT https://gitee.com/api/v5/notifications/messages
+func (s *ActivityService) MarkMessagesRead(ctx context.Context, markReq *MarkReadRequest) (*Response, error) {
+	req, err := s.client.NewRequest("PUT", "notifications/messages", markReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,215 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Notification identifies a gitee notification for a user.
+type Notification struct {
+	ID         *int64                   `json:"id,omitempty"`
+	Content    *string                  `json:"content,omitempty"`
+	Type       *string                  `json:"type,omitempty"` // 通知类型: event(动态通知), referer(@ 通知)
+	Unread     *bool                    `json:"unread,omitempty"`
+	Mute       *bool                    `json:"mute,omitempty"`
+	UpdatedAt  *time.Time               `json:"updated_at,omitempty"`
+	URL        *string                  `json:"url,omitempty"`
+	HTMLURL    *string                  `json:"html_url,omitempty"`
+	Actor      *User                    `json:"actor,omitempty"`
+	Repository *Repository              `json:"repository,omitempty"`
+	Subject    *NotificationSubject     `json:"subject,omitempty"`
+	Namespaces []*NotificationNamespace `json:"namespaces,omitempty"`
+}
+
+func (n Notification) String() string {
+	return Stringify(n)
+}
+
+// NotificationSubject identifies the subject of a notification.
+type NotificationSubject struct {
+	Title            *string `json:"title,omitempty"`
+	URL              *string `json:"url,omitempty"`
+	LatestCommentURL *string `json:"latest_comment_url,omitempty"`
+	Type             *string `json:"type,omitempty"`
+}
+
+// NotificationNamespace is the namespace (repository, issue, pull request ...)
+// a notification belongs to.
+type NotificationNamespace struct {
+	Name    *string `json:"name,omitempty"`
+	HTMLURL *string `json:"html_url,omitempty"`
+	Type    *string `json:"type,omitempty"`
+}
+
+// notificationList is the wrapper gitee returns around a list of notifications.
+type notificationList struct {
+	TotalCount *int            `json:"total_count,omitempty"`
+	List       []*Notification `json:"list,omitempty"`
+}
+
+// NotificationListOptions specifies the optional parameters to the
+// ActivityService.ListNotifications method.
+type NotificationListOptions struct {
+	Unread        bool   `url:"unread,omitempty"`        // 是否只获取未读消息，默认：否
+	Participating bool   `url:"participating,omitempty"` // 是否只获取自己直接参与的消息，默认：否
+	Type          string `url:"type,omitempty"`          // 筛选指定类型的通知，all：所有，event：事件通知，referer：@ 通知
+	Since         string `url:"since,omitempty"`         // 只获取在给定时间后更新的消息，要求时间格式为 ISO 8601
+	Before        string `url:"before,omitempty"`        // 只获取在给定时间前更新的消息，要求时间格式为 ISO 8601
+	IDs           string `url:"ids,omitempty"`           // 指定一组通知 ID，以 , 分隔
+
+	ListOptions
+}
+
+// ListNotifications lists all notifications for the authenticated user.
+//
+//  列出授权用户的所有通知 GET https://gitee.com/api/v5/notifications/threads
+func (s *ActivityService) ListNotifications(ctx context.Context, opts *NotificationListOptions) ([]*Notification, *Response, error) {
+	return s.listNotifications(ctx, "notifications/threads", opts)
+}
+
+// ListRepositoryNotifications lists all notifications in a given repository
+// for the authenticated user.
+//
+//  列出一个仓库里的通知 GET https://gitee.com/api/v5/repos/{owner}/{repo}/notifications
+func (s *ActivityService) ListRepositoryNotifications(ctx context.Context, owner, repo string, opts *NotificationListOptions) ([]*Notification, *Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/notifications", owner, repo)
+	return s.listNotifications(ctx, u, opts)
+}
+
+func (s *ActivityService) listNotifications(ctx context.Context, u string, opts *NotificationListOptions) ([]*Notification, *Response, error) {
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notifications := new(notificationList)
+	resp, err := s.client.Do(ctx, req, notifications)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return notifications.List, resp, nil
+}
+
+// NotificationCount is the number of notifications of the authenticated user.
+type NotificationCount struct {
+	TotalCount        *int `json:"total_count,omitempty"`        // 通知总数
+	NotificationCount *int `json:"notification_count,omitempty"` // 通知数
+	MessageCount      *int `json:"message_count,omitempty"`      // 私信数
+}
+
+func (n NotificationCount) String() string {
+	return Stringify(n)
+}
+
+type NotificationCountOptions struct {
+	Unread bool `url:"unread,omitempty"` // 是否只获取未读消息，默认：否
+}
+
+// CountNotifications gets the number of notifications for the authenticated user.
+//
+//  获取授权用户的通知数 GET https://gitee.com/api/v5/notifications/count
+func (s *ActivityService) CountNotifications(ctx context.Context, opts *NotificationCountOptions) (*NotificationCount, *Response, error) {
+	u, err := addOptions("notifications/count", opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	count := new(NotificationCount)
+	resp, err := s.client.Do(ctx, req, count)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return count, resp, nil
+}
+
+// GetThread gets the specified notification thread.
+//
+//  获取一条通知 GET https://gitee.com/api/v5/notifications/threads/{id}
+func (s *ActivityService) GetThread(ctx context.Context, id int64) (*Notification, *Response, error) {
+	u := fmt.Sprintf("notifications/threads/%v", id)
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notification := new(Notification)
+	resp, err := s.client.Do(ctx, req, notification)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return notification, resp, nil
+}
+
+// MarkThreadRead marks the specified thread as read.
+//
+//  标记一条通知为已读 PATCH https://gitee.com/api/v5/notifications/threads/{id}
+func (s *ActivityService) MarkThreadRead(ctx context.Context, id int64) (*Response, error) {
+	u := fmt.Sprintf("notifications/threads/%v", id)
+
+	req, err := s.client.NewRequest("PATCH", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+type MarkReadRequest struct {
+	IDs *string `json:"ids,omitempty"` // 指定一组通知 ID，以 , 分隔，缺省标记全部
+}
+
+// MarkNotificationsRead marks all notifications, or the ones listed in
+// markReq.IDs, as read.
+//
+//  标记所有通知为已读 PUT https://gitee.com/api/v5/notifications/threads
+func (s *ActivityService) MarkNotificationsRead(ctx context.Context, markReq *MarkReadRequest) (*Response, error) {
+	req, err := s.client.NewRequest("PUT", "notifications/threads", markReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// MarkRepositoryNotificationsRead marks all notifications in a given repository
+// as read.
+//
+//  标记一个仓库里的通知为已读 PUT https://gitee.com/api/v5/repos/{owner}/{repo}/notifications
+func (s *ActivityService) MarkRepositoryNotificationsRead(ctx context.Context, owner, repo string, markReq *MarkReadRequest) (*Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/notifications", owner, repo)
+	req, err := s.client.NewRequest("PUT", u, markReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
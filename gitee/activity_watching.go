@@ -0,0 +1,97 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListWatched lists the repositories the specified user is watching. Passing
+// the empty string will fetch watched repos for the authenticated user.
+//
+//  列出授权用户 watch 了的仓库 GET https://gitee.com/api/v5/user/subscriptions
+//  列出用户 watch 了的仓库 GET https://gitee.com/api/v5/users/{username}/subscriptions
+func (s *ActivityService) ListWatched(ctx context.Context, user string, opts *ActivityListStarredOptions) ([]*Repository, *Response, error) {
+	var u string
+	if user != "" {
+		u = fmt.Sprintf("users/%v/subscriptions", user)
+	} else {
+		u = "user/subscriptions"
+	}
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var watched []*Repository
+	resp, err := s.client.Do(ctx, req, &watched)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return watched, resp, nil
+}
+
+// IsWatching checks if the authenticated user is watching a repository.
+//
+//  检查授权用户是否 watch 了一个仓库 GET https://gitee.com/api/v5/user/subscriptions/{owner}/{repo}
+func (s *ActivityService) IsWatching(ctx context.Context, owner, repo string) (bool, *Response, error) {
+	u := fmt.Sprintf("user/subscriptions/%v/%v", owner, repo)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return false, nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req, nil)
+	watching, err := parseBoolResponse(err)
+	return watching, resp, err
+}
+
+type WatchRequest struct {
+	// WatchType 关注类型: subscribed(关注所有动态), releases_subscribed(只关注版本发行), ignoring(忽略/不关注)
+	WatchType *string `json:"watch_type"`
+}
+
+// Watch a repository as the authenticated user.
+//
+//  watch 一个仓库 PUT https://gitee.com/api/v5/user/subscriptions/{owner}/{repo}
+func (s *ActivityService) Watch(ctx context.Context, owner, repo string, watchReq *WatchRequest) (*Response, error) {
+	u := fmt.Sprintf("user/subscriptions/%v/%v", owner, repo)
+	req, err := s.client.NewRequest("PUT", u, watchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// Unwatch a repository as the authenticated user.
+//
+//  取消 watch 一个仓库 DELETE https://gitee.com/api/v5/user/subscriptions/{owner}/{repo}
+func (s *ActivityService) Unwatch(ctx context.Context, owner, repo string) (*Response, error) {
+	u := fmt.Sprintf("user/subscriptions/%v/%v", owner, repo)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
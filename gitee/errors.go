@@ -0,0 +1,182 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// The kinds of API errors. *ErrorResponse matches one of them with errors.Is:
+//
+//  if errors.Is(err, gitee.ErrNotFound) { ... }
+var (
+	ErrUnauthorized = errors.New("gitee: unauthorized")
+	ErrForbidden    = errors.New("gitee: forbidden")
+	ErrNotFound     = errors.New("gitee: not found")
+	ErrValidation   = errors.New("gitee: validation failed")
+	ErrConflict     = errors.New("gitee: already exists")
+	ErrServerError  = errors.New("gitee: server error")
+)
+
+// ErrorCode is a stable code of an API error, derived from the status code
+// and gitee's message, so callers do not need to match message strings.
+type ErrorCode string
+
+const (
+	ErrorCodeUnknown      ErrorCode = ""
+	ErrorCodeUnauthorized ErrorCode = "unauthorized"
+	ErrorCodeTokenExpired ErrorCode = "token_expired"
+	ErrorCodeForbidden    ErrorCode = "forbidden"
+	ErrorCodeNotFound     ErrorCode = "not_found"
+	ErrorCodeValidation   ErrorCode = "validation_failed"
+	ErrorCodeConflict     ErrorCode = "already_exists"
+	ErrorCodeServerError  ErrorCode = "server_error"
+
+	ErrorCodeBranchExists      ErrorCode = "branch_exists"
+	ErrorCodeTagExists         ErrorCode = "tag_exists"
+	ErrorCodeRepositoryExists  ErrorCode = "repository_exists"
+	ErrorCodePullRequestExists ErrorCode = "pull_request_exists"
+	ErrorCodeBranchNotFound    ErrorCode = "branch_not_found"
+	ErrorCodeNoPermission      ErrorCode = "no_permission"
+)
+
+// errorCodeKinds maps every code to the kind errors.Is matches.
+var errorCodeKinds = map[ErrorCode]error{
+	ErrorCodeUnauthorized:      ErrUnauthorized,
+	ErrorCodeTokenExpired:      ErrUnauthorized,
+	ErrorCodeForbidden:         ErrForbidden,
+	ErrorCodeNoPermission:      ErrForbidden,
+	ErrorCodeNotFound:          ErrNotFound,
+	ErrorCodeBranchNotFound:    ErrNotFound,
+	ErrorCodeValidation:        ErrValidation,
+	ErrorCodeConflict:          ErrConflict,
+	ErrorCodeBranchExists:      ErrConflict,
+	ErrorCodeTagExists:         ErrConflict,
+	ErrorCodeRepositoryExists:  ErrConflict,
+	ErrorCodePullRequestExists: ErrConflict,
+	ErrorCodeServerError:       ErrServerError,
+}
+
+// messageCodes maps gitee's common messages to codes, checked in order.
+// gitee 的错误码不统一, 例如分支已存在返回的是 400, 所以要看 message
+var messageCodes = []struct {
+	substr string
+	code   ErrorCode
+}{
+	{"分支名已存在", ErrorCodeBranchExists},
+	{"分支已存在", ErrorCodeBranchExists},
+	{"Branch name already exists", ErrorCodeBranchExists},
+	{"标签名已存在", ErrorCodeTagExists},
+	{"标签已存在", ErrorCodeTagExists},
+	{"Tag name already exists", ErrorCodeTagExists},
+	{"仓库名已存在", ErrorCodeRepositoryExists},
+	{"已存在同名的仓库", ErrorCodeRepositoryExists},
+	{"已存在相同源分支", ErrorCodePullRequestExists},
+	{"分支不存在", ErrorCodeBranchNotFound},
+	{"Branch Not Found", ErrorCodeBranchNotFound},
+	{"Access token is expired", ErrorCodeTokenExpired},
+	{"没有权限", ErrorCodeNoPermission},
+	{"权限不足", ErrorCodeNoPermission},
+	{"已存在", ErrorCodeConflict},
+	{"already exists", ErrorCodeConflict},
+	{"不存在", ErrorCodeNotFound},
+}
+
+// errorCode returns the code of an error response. For 400, 422 and unknown
+// status codes gitee's message decides the code, any other status code keeps
+// its kind whatever the message.
+func errorCode(statusCode int, message string) ErrorCode {
+	code := ErrorCodeUnknown
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		code = ErrorCodeUnauthorized
+	case statusCode == http.StatusForbidden:
+		code = ErrorCodeForbidden
+	case statusCode == http.StatusNotFound:
+		code = ErrorCodeNotFound
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusUnprocessableEntity:
+		code = ErrorCodeValidation
+	case statusCode == http.StatusConflict:
+		code = ErrorCodeConflict
+	case statusCode >= 500:
+		code = ErrorCodeServerError
+	}
+
+	lower := strings.ToLower(message)
+	for _, m := range messageCodes {
+		if !strings.Contains(lower, strings.ToLower(m.substr)) {
+			continue
+		}
+		// 只有 400/422 和未知的状态码才按提示信息归类, 其他的状态码不能
+		// 改变错误的种类, 例如 404 的提示里面有 "已存在" 也还是 ErrNotFound
+		if code != ErrorCodeValidation && code != ErrorCodeUnknown && errorCodeKinds[m.code] != errorCodeKinds[code] {
+			continue
+		}
+		return m.code
+	}
+	return code
+}
+
+// FieldError is the error of a single request parameter of a validation
+// error.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// parseFieldErrors parses gitee's validation message like
+// "title is missing, head is invalid" into one FieldError per parameter.
+func parseFieldErrors(message string) []FieldError {
+	var fields []FieldError
+	for _, part := range strings.Split(message, ", ") {
+		i := strings.Index(part, " ")
+		if i <= 0 {
+			return nil
+		}
+		field, msg := part[:i], part[i+1:]
+		if !strings.HasPrefix(msg, "is ") && !strings.HasPrefix(msg, "does ") && !strings.HasPrefix(msg, "must ") {
+			return nil
+		}
+		fields = append(fields, FieldError{Field: field, Message: msg})
+	}
+	return fields
+}
+
+// Is reports whether target is the kind of the error, e.g. ErrNotFound.
+func (r *ErrorResponse) Is(target error) bool {
+	kind, ok := errorCodeKinds[r.Code]
+	return ok && kind == target
+}
+
+// IsUnauthorized reports whether err is an ErrUnauthorized API error.
+func IsUnauthorized(err error) bool { return errors.Is(err, ErrUnauthorized) }
+
+// IsForbidden reports whether err is an ErrForbidden API error.
+func IsForbidden(err error) bool { return errors.Is(err, ErrForbidden) }
+
+// IsNotFound reports whether err is an ErrNotFound API error.
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsValidation reports whether err is an ErrValidation API error.
+func IsValidation(err error) bool { return errors.Is(err, ErrValidation) }
+
+// IsConflict reports whether err is an ErrConflict API error, i.e. the
+// branch, tag, repository or pull request already exists.
+func IsConflict(err error) bool { return errors.Is(err, ErrConflict) }
+
+// IsServerError reports whether err is an ErrServerError API error.
+func IsServerError(err error) bool { return errors.Is(err, ErrServerError) }
@@ -0,0 +1,83 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event represents a gitee event.
+type Event struct {
+	ID         *int64           `json:"id,omitempty"`
+	Type       *string          `json:"type,omitempty"`
+	Actor      *User            `json:"actor,omitempty"`
+	Repo       *Repository      `json:"repo,omitempty"`
+	Org        *Organization    `json:"org,omitempty"`
+	Public     *bool            `json:"public,omitempty"`
+	CreatedAt  *time.Time       `json:"created_at,omitempty"`
+	RawPayload *json.RawMessage `json:"payload,omitempty"`
+}
+
+func (e Event) String() string {
+	return Stringify(e)
+}
+
+// ParsePayload parses the event payload. For recognized event types,
+// a value of the corresponding struct type will be returned.
+// 未识别的动态类型 返回 map[string]interface{} 形式的 payload
+func (e *Event) ParsePayload() (payload interface{}, err error) {
+	switch e.GetType() {
+	case "CreateEvent":
+		payload = &CreateEvent{}
+	case "DeleteEvent":
+		payload = &DeleteEvent{}
+	case "ForkEvent":
+		payload = &ForkEvent{}
+	case "IssueEvent":
+		payload = &IssueEvent{}
+	case "IssueCommentEvent":
+		payload = &IssueCommentEvent{}
+	case "CommitCommentEvent":
+		payload = &CommitCommentEvent{}
+	case "PullRequestEvent":
+		payload = &PullRequestEvent{}
+	case "PullRequestCommentEvent":
+		payload = &PullRequestCommentEvent{}
+	case "PushEvent":
+		payload = &PushEvent{}
+	case "StarEvent":
+		payload = &StarEvent{}
+	case "WatchEvent":
+		payload = &WatchEvent{}
+	case "MemberEvent":
+		payload = &MemberEvent{}
+	default:
+		payload = &map[string]interface{}{}
+	}
+	if e.RawPayload == nil {
+		return payload, nil
+	}
+	err = json.Unmarshal(*e.RawPayload, payload)
+	return payload, err
+}
+
+// GetType returns the Type field if it's non-nil, zero value otherwise.
+func (e *Event) GetType() string {
+	if e == nil || e.Type == nil {
+		return ""
+	}
+	return *e.Type
+}
@@ -0,0 +1,109 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+// These event types are shared between the Events API and used as payloads
+// of the corresponding Event.Type. 这里是 动态(Event) 里面 payload 对应的类型
+
+// CreateEvent represents a created repository, branch, or tag.
+type CreateEvent struct {
+	Ref *string `json:"ref,omitempty"`
+	// RefType is the object that was created. Possible values are: "repository", "branch", "tag".
+	RefType      *string `json:"ref_type,omitempty"`
+	MasterBranch *string `json:"master_branch,omitempty"`
+	Description  *string `json:"description,omitempty"`
+}
+
+// DeleteEvent represents a deleted branch or tag.
+type DeleteEvent struct {
+	Ref *string `json:"ref,omitempty"`
+	// RefType is the object that was deleted. Possible values are: "branch", "tag".
+	RefType *string `json:"ref_type,omitempty"`
+}
+
+// ForkEvent is triggered when a user forks a repository.
+// payload 就是 fork 出来的新仓库
+type ForkEvent struct {
+	*Repository
+}
+
+// IssueEvent is triggered when an issue is created, edited or its state changes.
+// payload 就是 issue 本身，外加一个 action 字段
+type IssueEvent struct {
+	Action *string `json:"action,omitempty"`
+	*Issue
+}
+
+// IssueCommentEvent is triggered when an issue comment is created.
+type IssueCommentEvent struct {
+	Action  *string       `json:"action,omitempty"`
+	Issue   *Issue        `json:"issue,omitempty"`
+	Comment *IssueComment `json:"comment,omitempty"`
+}
+
+// CommitCommentEvent is triggered when a commit comment is created.
+type CommitCommentEvent struct {
+	Action  *string            `json:"action,omitempty"`
+	Comment *RepositoryComment `json:"comment,omitempty"`
+}
+
+// PullRequestEvent is triggered when a pull request is opened, closed,
+// merged, or updated. payload 就是 pull request 本身，外加一个 action 字段
+type PullRequestEvent struct {
+	Action *string `json:"action,omitempty"`
+	*PullRequest
+}
+
+// PullRequestCommentEvent is triggered when a comment is created on a pull request.
+type PullRequestCommentEvent struct {
+	Action      *string            `json:"action,omitempty"`
+	PullRequest *PullRequest       `json:"pull_request,omitempty"`
+	Comment     *RepositoryComment `json:"comment,omitempty"`
+}
+
+// PushEvent represents a git push to a repository.
+type PushEvent struct {
+	Ref     *string            `json:"ref,omitempty"`
+	Before  *string            `json:"before,omitempty"`
+	After   *string            `json:"after,omitempty"`
+	Created *bool              `json:"created,omitempty"`
+	Deleted *bool              `json:"deleted,omitempty"`
+	Size    *int               `json:"size,omitempty"`
+	Commits []*PushEventCommit `json:"commits,omitempty"`
+}
+
+// PushEventCommit represents a git commit in a push event.
+type PushEventCommit struct {
+	SHA     *string       `json:"sha,omitempty"`
+	Message *string       `json:"message,omitempty"`
+	Author  *CommitAuthor `json:"author,omitempty"`
+	URL     *string       `json:"url,omitempty"`
+}
+
+// StarEvent is triggered when a repository is starred.
+type StarEvent struct {
+	Action *string `json:"action,omitempty"`
+}
+
+// WatchEvent is triggered when a user watches a repository.
+type WatchEvent struct {
+	Action *string `json:"action,omitempty"`
+}
+
+// MemberEvent is triggered when a user is added as a collaborator to a repository.
+type MemberEvent struct {
+	Action *string `json:"action,omitempty"`
+	Member *User   `json:"member,omitempty"`
+}
@@ -29,6 +29,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -36,7 +37,13 @@ import (
 const (
 	Version          = "v1.0.0"
 	defaultBaseURL   = "https://gitee.com/api/v5/"
+	defaultWebURL    = "https://gitee.com/"
 	defaultUserAgent = "go-gitee" + "/" + Version
+
+	headerRateLimit     = "X-RateLimit-Limit"
+	headerRateRemaining = "X-RateLimit-Remaining"
+	headerRateReset     = "X-RateLimit-Reset"
+	headerRetryAfter    = "Retry-After"
 )
 
 var errNonNilContext = errors.New("context must be non-nil")
@@ -51,9 +58,30 @@ type Client struct {
 	// always be specified with a trailing slash.
 	BaseURL *url.URL
 
+	// Web URL of the gitee site, e.g. for web links and the OAuth endpoints.
+	// Defaults to https://gitee.com/, NewEnterpriseClient sets it to the host
+	// of the self-hosted deployment.
+	WebURL *url.URL
+
 	// User agent used when communicating with the GitHub API.
 	UserAgent string
 
+	// Timeout, if set, limits every call including its retries and reading
+	// the response body. It can be overridden per call with WithTimeout.
+	// A call that runs out of time returns context.DeadlineExceeded.
+	Timeout time.Duration
+
+	// Observer, if set, is told about every call, e.g. for logging and
+	// metrics. See LogObserver and PrometheusObserver.
+	Observer Observer
+
+	// RetryPolicy, if set, retries requests failing with transient errors.
+	// It can be overridden per call with WithRetryPolicy.
+	RetryPolicy *RetryPolicy
+
+	rateMu     sync.Mutex
+	rateLimits Rate // Rate limits for the client as determined by the most recent API calls.
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
 	// Services used for talking to different parts of the gitee API.
@@ -94,8 +122,9 @@ func NewClient(httpClient *http.Client) *Client {
 	}
 
 	baseURL, _ := url.Parse(defaultBaseURL)
+	webURL, _ := url.Parse(defaultWebURL)
 
-	c := &Client{client: httpClient, BaseURL: baseURL, UserAgent: defaultUserAgent}
+	c := &Client{client: httpClient, BaseURL: baseURL, WebURL: webURL, UserAgent: defaultUserAgent}
 	c.common.client = c
 
 	c.Users = (*UsersService)(&c.common)
@@ -136,6 +165,10 @@ type Response struct {
 
 	TotalCount int
 	TotalPage  int
+
+	// Explicitly specify the Rate type so Rate's String() receiver doesn't
+	// propagate to Response.
+	Rate Rate
 }
 
 // newResponse creates a new Response for the provided http.Response.
@@ -144,9 +177,48 @@ func newResponse(r *http.Response) *Response {
 	response := &Response{Response: r}
 	response.populatePageValues()
 	response.parseOther()
+	response.Rate = parseRate(r)
 	return response
 }
 
+// parseRate parses the rate related headers. gitee 的限流信息不一定总会返回,
+// 被限流时可能只有 Retry-After, 这种情况下 Remaining 记为 0
+func parseRate(r *http.Response) Rate {
+	var rate Rate
+	if limit := r.Header.Get(headerRateLimit); limit != "" {
+		rate.Limit, _ = strconv.Atoi(limit)
+	}
+	if remaining := r.Header.Get(headerRateRemaining); remaining != "" {
+		rate.Remaining, _ = strconv.Atoi(remaining)
+	}
+	if reset := r.Header.Get(headerRateReset); reset != "" {
+		if v, _ := strconv.ParseInt(reset, 10, 64); v != 0 {
+			rate.Reset = Timestamp{time.Unix(v, 0)}
+		}
+	}
+	if rate.Reset.IsZero() {
+		if retryAfter := parseRetryAfter(r.Header.Get(headerRetryAfter)); retryAfter > 0 {
+			rate.Reset = Timestamp{time.Now().Add(retryAfter)}
+		}
+	}
+	return rate
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as
+// an HTTP date. It returns 0 if the header is absent or invalid.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
 func (r *Response) parseOther() {
 	if tc := r.Header.Get("Total_count"); tc != "" {
 		r.TotalCount, _ = strconv.Atoi(tc)
@@ -242,18 +314,27 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	return req, nil
 }
 
-// sanitizeURL redacts the client_secret parameter from the URL which may be
-// exposed to the user.
+// sanitizeURL redacts the client_secret and access_token parameters from the
+// URL which may be exposed to the user. uri itself is not modified, since it is
+// usually the URL of a request.
 func sanitizeURL(uri *url.URL) *url.URL {
 	if uri == nil {
 		return nil
 	}
 	params := uri.Query()
-	if len(params.Get("client_secret")) > 0 {
-		params.Set("client_secret", "REDACTED")
-		uri.RawQuery = params.Encode()
+	redacted := false
+	for _, key := range []string{"client_secret", "access_token"} {
+		if len(params.Get(key)) > 0 {
+			params.Set(key, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return uri
 	}
-	return uri
+	u := *uri
+	u.RawQuery = params.Encode()
+	return &u
 }
 
 type AcceptedError struct {
@@ -274,10 +355,107 @@ func (ae *AcceptedError) Is(target error) bool {
 	return bytes.Compare(ae.Raw, v.Raw) == 0
 }
 
+// Rate represents the rate limit for the current client.
+type Rate struct {
+	// The number of requests per hour the client is currently limited to.
+	Limit int `json:"limit"`
+
+	// The number of remaining requests the client can make this hour.
+	Remaining int `json:"remaining"`
+
+	// The time at which the current rate limit will reset.
+	Reset Timestamp `json:"reset"`
+}
+
+func (r Rate) String() string {
+	return Stringify(r)
+}
+
+// RateLimitError occurs when gitee returns 429 Too Many Requests, or 403
+// Forbidden with a rate limit message or exhausted rate limit headers.
+// 接口访问频率超限
+type RateLimitError struct {
+	Rate     Rate           // Rate specifies last known rate limit for the client
+	Response *http.Response // HTTP response that caused this error
+	Message  string         `json:"message"` // error message
+}
+
+func (r *RateLimitError) Error() string {
+	return fmt.Sprintf("%v %v: %d %v %v",
+		r.Response.Request.Method, sanitizeURL(r.Response.Request.URL),
+		r.Response.StatusCode, r.Message, formatRateReset(time.Until(r.Rate.Reset.Time)))
+}
+
+// Is returns whether the provided error equals this error.
+func (r *RateLimitError) Is(target error) bool {
+	v, ok := target.(*RateLimitError)
+	if !ok {
+		return false
+	}
+
+	return r.Rate == v.Rate &&
+		r.Message == v.Message &&
+		compareHTTPResponse(r.Response, v.Response)
+}
+
+// compareHTTPResponse returns whether two http.Response objects are equal or not.
+// Currently, only StatusCode is checked. This function is used when implementing the
+// Is(error) bool interface for the custom error types in this package.
+func compareHTTPResponse(r1, r2 *http.Response) bool {
+	if r1 == nil && r2 == nil {
+		return true
+	}
+
+	if r1 != nil && r2 != nil {
+		return r1.StatusCode == r2.StatusCode
+	}
+	return false
+}
+
+// formatRateReset formats d to look like "[rate reset in 2s]" or
+// "[rate reset in 87m02s]" for the positive durations. And like "[rate limit was reset 87m02s ago]"
+// for the negative cases.
+func formatRateReset(d time.Duration) string {
+	isNegative := d < 0
+	if isNegative {
+		d *= -1
+	}
+	secondsTotal := int(0.5 + d.Seconds())
+	minutes := secondsTotal / 60
+	seconds := secondsTotal - minutes*60
+
+	var timeString string
+	if minutes > 0 {
+		timeString = fmt.Sprintf("%dm%02ds", minutes, seconds)
+	} else {
+		timeString = fmt.Sprintf("%ds", seconds)
+	}
+
+	if isNegative {
+		return fmt.Sprintf("[rate limit was reset %v ago]", timeString)
+	}
+	return fmt.Sprintf("[rate reset in %v]", timeString)
+}
+
+// isRateLimited reports whether a 403 response is caused by rate limiting.
+func isRateLimited(r *http.Response, message string) bool {
+	if r.Header.Get(headerRateRemaining) == "0" {
+		return true
+	}
+	m := strings.ToLower(message)
+	return strings.Contains(m, "rate limit") || strings.Contains(m, "too many requests") ||
+		strings.Contains(message, "频率") || strings.Contains(message, "过于频繁")
+}
+
+// ErrorResponse reports an error caused by an API request. Use errors.Is with
+// ErrNotFound and the other kinds in errors.go, or Code, to tell errors apart.
 type ErrorResponse struct {
 	Response *http.Response         // HTTP response that caused this error
 	ErrorMap map[string]interface{} `json:"error"`   // more detail on individual errors
 	Message  string                 `json:"message"` // error message
+
+	Code   ErrorCode    `json:"-"`      // stable code derived from the status code and message
+	Errors []FieldError `json:"errors"` // parameters failing validation, if any
 }
 
 func (r *ErrorResponse) Error() string {
@@ -305,14 +483,20 @@ func CheckResponse(r *http.Response) error {
 	}
 
 	r.Body = ioutil.NopCloser(bytes.NewBuffer(data))
-	switch {
-	case r.StatusCode == http.StatusUnauthorized: // 401 error
-		return errorResponse
-	case r.StatusCode == http.StatusForbidden: // 403 error
-		return errorResponse
-	default: // 以上几个报错, github 这里的报错会有 是由于api 接口访问频率限制等等
-		return errorResponse
+	if r.StatusCode == http.StatusTooManyRequests ||
+		r.StatusCode == http.StatusForbidden && isRateLimited(r, errorResponse.Message) { // 接口访问频率限制
+		return &RateLimitError{
+			Rate:     parseRate(r),
+			Response: errorResponse.Response,
+			Message:  errorResponse.Message,
+		}
+	}
+
+	errorResponse.Code = errorCode(r.StatusCode, errorResponse.Message)
+	if errorResponse.Code == ErrorCodeValidation && len(errorResponse.Errors) == 0 {
+		errorResponse.Errors = parseFieldErrors(errorResponse.Message)
 	}
+	return errorResponse
 }
 
 // parseBoolResponse determines the boolean result from a API response.
@@ -341,37 +525,74 @@ func parseBoolResponse(err error) (bool, error) {
 // without making a network API call.
 //
 // The provided ctx must be non-nil, if it is nil an error is returned. If it is
-// canceled or times out, ctx.Err() will be returned.
+// canceled or times out, or the call exceeds Client.Timeout, ctx.Err() will be
+// returned, also while reading the body.
 func (c *Client) BareDo(ctx context.Context, req *http.Request) (*Response, error) {
 	if ctx == nil {
 		return nil, errNonNilContext
 	}
-	resp, err := c.client.Do(req)
+
+	// If we've hit rate limit, don't make further requests before Reset time.
+	if err := c.checkRateLimitBeforeDo(req); err != nil {
+		return &Response{
+			Response: err.Response,
+			Rate:     err.Rate,
+		}, err
+	}
+
+	ctx, cancel := c.callContext(ctx)
+	req = req.WithContext(ctx)
+
+	start := time.Now()
+	resp, attempts, err := c.doWithRetry(ctx, req)
 	if err != nil {
+		defer cancel()
+
 		// If we got an error, and the context has been canceled,
 		// the context's error is probably more useful.
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			err = ctx.Err()
 		default:
-		}
-
-		// If the error type is *url.Error, sanitize its URL before returning.
-		if e, ok := err.(*url.Error); ok {
-			if url, err := url.Parse(e.URL); err == nil {
-				e.URL = sanitizeURL(url).String()
-				return nil, e
+			// If the error type is *url.Error, sanitize its URL before returning.
+			if e, ok := err.(*url.Error); ok {
+				if url, perr := url.Parse(e.URL); perr == nil {
+					e.URL = sanitizeURL(url).String()
+				}
 			}
 		}
 
+		c.observe(ctx, req, nil, start, attempts, 0, err)
 		return nil, err
 	}
+	body := &callBody{ReadCloser: resp.Body, ctx: ctx, cancel: cancel}
+	if c.Observer != nil {
+		body.done = func(n int64) {
+			c.observe(ctx, req, resp, start, attempts, n, nil)
+		}
+	}
+	resp.Body = body
 
 	response := newResponse(resp)
 
 	err = CheckResponse(resp)
+	if rerr, ok := err.(*RateLimitError); ok {
+		c.rateMu.Lock()
+		c.rateLimits = rerr.Rate
+		if c.rateLimits.Reset.IsZero() { // 没有给出恢复时间的, 至少等 1 分钟再试
+			c.rateLimits.Reset = Timestamp{time.Now().Add(time.Minute)}
+		}
+		c.rateLimits.Remaining = 0
+		c.rateMu.Unlock()
+	} else if response.Rate.Limit > 0 {
+		c.rateMu.Lock()
+		c.rateLimits = response.Rate
+		c.rateMu.Unlock()
+	}
+
 	if err != nil { // 这里 特殊处理 AcceptedError 这种错误，提交 返回了
 		defer resp.Body.Close() // 这里就提前关闭了, 其他返回的 会在调用的 地方 func Do 里面关闭
+		defer body.Close()      // CheckResponse 可能已经把 Body 换掉了
 
 		aerr, ok := err.(*AcceptedError)
 		if ok {
@@ -387,6 +608,42 @@ func (c *Client) BareDo(ctx context.Context, req *http.Request) (*Response, erro
 	return response, err
 }
 
+// checkRateLimitBeforeDo does not make any network calls, but uses existing
+// knowledge from current client state in order to quickly check if
+// *RateLimitError can be immediately returned from Client.Do, and if so,
+// returns it so that Client.Do can skip making a network API call unnecessarily.
+// Otherwise it returns nil, and Client.Do should proceed normally.
+func (c *Client) checkRateLimitBeforeDo(req *http.Request) *RateLimitError {
+	c.rateMu.Lock()
+	rate := c.rateLimits
+	c.rateMu.Unlock()
+	if !rate.Reset.Time.IsZero() && rate.Remaining == 0 && time.Now().Before(rate.Reset.Time) {
+		// Create a fake response.
+		resp := &http.Response{
+			Status:     http.StatusText(http.StatusForbidden),
+			StatusCode: http.StatusForbidden,
+			Request:    req,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+		return &RateLimitError{
+			Rate:     rate,
+			Response: resp,
+			Message:  fmt.Sprintf("API rate limit still exceeded until %v, not making remote request.", rate.Reset.Time),
+		}
+	}
+
+	return nil
+}
+
+// RateLimit returns the rate limit of the client as determined by the most
+// recent API call. It is the zero Rate if gitee has not reported one yet.
+func (c *Client) RateLimit() Rate {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return c.rateLimits
+}
+
 // Do sends an API request and returns the API response. The API response is
 // JSON decoded and stored in the value pointed to by v, or returned as an
 // error if an API error has occurred. If v implements the io.Writer interface,
@@ -0,0 +1,271 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// XFromCacheHeader is set to "1" on responses served by CachingTransport
+// from its storage after the server answered 304 Not Modified.
+const XFromCacheHeader = "X-From-Cache"
+
+// CacheStorage stores the raw responses cached by CachingTransport.
+// Implementations must be safe for concurrent use.
+type CacheStorage interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	Delete(key string)
+}
+
+// CachingTransport is an http.RoundTripper caching GET responses that carry
+// an ETag or Last-Modified header. A cached request is revalidated with
+// If-None-Match / If-Modified-Since, a 304 Not Modified answer is served from
+// the cache. Other methods are passed through and drop the cached response
+// of their URL.
+//
+// Example usage:
+//
+//  cache := &gitee.CachingTransport{Storage: gitee.NewMemoryCache(1000)}
+//  client := gitee.NewClient(cache.Client())
+//
+// With oauth2, put the cache under the oauth2 transport so the Authorization
+// header becomes part of the cache key:
+//
+//  tc := &http.Client{Transport: &oauth2.Transport{Source: ts, Base: cache}}
+type CachingTransport struct {
+	// Transport is the transport used for the requests, default is
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Storage stores the responses, default is an in-memory cache of
+	// 1000 responses.
+	Storage CacheStorage
+
+	once sync.Once
+}
+
+// Client returns an *http.Client using the transport.
+func (t *CachingTransport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+func (t *CachingTransport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+func (t *CachingTransport) storage() CacheStorage {
+	t.once.Do(func() {
+		if t.Storage == nil {
+			t.Storage = NewMemoryCache(1000)
+		}
+	})
+	return t.Storage
+}
+
+// cacheKey 区分不同的账号, 同一个 URL 不同的 token 不能共用缓存
+func cacheKey(req *http.Request) string {
+	h := sha256.New()
+	h.Write([]byte(req.URL.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Header.Get("Authorization")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	storage := t.storage()
+	key := cacheKey(req)
+
+	if req.Method != http.MethodGet {
+		resp, err := t.transport().RoundTrip(req)
+		if err == nil && req.Method != http.MethodHead && resp.StatusCode < 400 {
+			storage.Delete(key)
+		}
+		return resp, err
+	}
+
+	var cached *http.Response
+	if raw, ok := storage.Get(key); ok {
+		cached, _ = http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), req)
+	}
+	if cached != nil && req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == "" {
+		// RoundTrip 不能修改调用方的 req
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	} else {
+		cached = nil
+	}
+
+	resp, err := t.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		// 304 里面的头, 例如限流的头, 比缓存里面的新
+		for k, v := range resp.Header {
+			cached.Header[k] = v
+		}
+		cached.Header.Set(XFromCacheHeader, "1")
+		cached.Request = req
+		return cached, nil
+	}
+
+	if resp.StatusCode == http.StatusOK && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "") {
+		if raw, err := httputil.DumpResponse(resp, true); err == nil {
+			storage.Set(key, raw)
+		}
+	} else if resp.StatusCode != http.StatusNotModified {
+		storage.Delete(key)
+	}
+	return resp, nil
+}
+
+// MemoryCache is an in-memory CacheStorage evicting the least recently used
+// response once it holds more than its size.
+type MemoryCache struct {
+	size int
+
+	mu    sync.Mutex
+	items map[string]*list.Element
+	order *list.List // 最近用过的在最前面
+}
+
+// memoryCacheEntry is an entry of MemoryCache.
+type memoryCacheEntry struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryCache returns a MemoryCache holding at most size responses.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{size: size, items: make(map[string]*list.Element), order: list.New()}
+}
+
+// Get implements the CacheStorage interface.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*memoryCacheEntry).value, true
+}
+
+// Set implements the CacheStorage interface.
+func (c *MemoryCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.Value.(*memoryCacheEntry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.order.PushFront(&memoryCacheEntry{key: key, value: value})
+	for c.size > 0 && c.order.Len() > c.size {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.items, e.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Delete implements the CacheStorage interface.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.order.Remove(e)
+		delete(c.items, key)
+	}
+}
+
+// DiskCache is a CacheStorage keeping one file per response in a directory,
+// so the cache survives restarts.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a DiskCache storing its files in dir, which is
+// created if it does not exist.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (c *DiskCache) path(key string) string {
+	// key 已经是 sha256 的 hex 了, 其他的 key 也 hash 一下防止出现路径分隔符
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements the CacheStorage interface.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	value, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set implements the CacheStorage interface. The file is written to a
+// temporary file first, so concurrent readers never see half a response.
+func (c *DiskCache) Set(key string, value []byte) {
+	f, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(value)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// Delete implements the CacheStorage interface.
+func (c *DiskCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
@@ -14,8 +14,61 @@
 
 package gitee
 
+import "time"
+
 // IssuesService handles communication with the issue related
 // methods of the gitee API.
 type IssuesService service
 
 // TODO 获取仓库所有Issue的评论 GET https://gitee.com/api/v5/repos/{owner}/{repo}/issues/comments
+
+// Issue represents a gitee issue on a repository.
+type Issue struct {
+	ID            *int64      `json:"id,omitempty"`
+	URL           *string     `json:"url,omitempty"`
+	RepositoryURL *string     `json:"repository_url,omitempty"`
+	LabelsURL     *string     `json:"labels_url,omitempty"`
+	CommentsURL   *string     `json:"comments_url,omitempty"`
+	HTMLURL       *string     `json:"html_url,omitempty"`
+	ParentURL     *string     `json:"parent_url,omitempty"`
+	Number        *string     `json:"number,omitempty"` // gitee 的 issue 编号是字符串，如 I4ABCD
+	ParentID      *int64      `json:"parent_id,omitempty"`
+	Depth         *int        `json:"depth,omitempty"`
+	State         *string     `json:"state,omitempty"` // 状态: open, progressing, closed, rejected
+	Title         *string     `json:"title,omitempty"`
+	Body          *string     `json:"body,omitempty"`
+	User          *User       `json:"user,omitempty"`
+	Labels        []*Label    `json:"labels,omitempty"`
+	Assignee      *User       `json:"assignee,omitempty"`
+	Collaborators []*User     `json:"collaborators,omitempty"`
+	Repository    *Repository `json:"repository,omitempty"`
+	Milestone     *Milestone  `json:"milestone,omitempty"`
+	CreatedAt     *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time  `json:"updated_at,omitempty"`
+	PlanStartedAt *time.Time  `json:"plan_started_at,omitempty"`
+	Deadline      *time.Time  `json:"deadline,omitempty"`
+	FinishedAt    *time.Time  `json:"finished_at,omitempty"`
+	Comments      *int        `json:"comments,omitempty"`
+	Priority      *int        `json:"priority,omitempty"`   // 优先级(0: 不指定 1: 不重要 2: 次要 3: 主要 4: 严重)
+	IssueType     *string     `json:"issue_type,omitempty"` // 任务类型，如: 任务、缺陷
+	SecurityHole  *bool       `json:"security_hole,omitempty"`
+}
+
+func (i Issue) String() string {
+	return Stringify(i)
+}
+
+// IssueComment represents a comment left on an issue.
+type IssueComment struct {
+	ID        *int64         `json:"id,omitempty"`
+	Body      *string        `json:"body,omitempty"`
+	User      *User          `json:"user,omitempty"`
+	Source    *string        `json:"source,omitempty"`
+	Target    *CommentTarget `json:"target,omitempty"`
+	CreatedAt *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"`
+}
+
+func (i IssueComment) String() string {
+	return Stringify(i)
+}
@@ -14,4 +14,21 @@
 
 package gitee
 
+import "time"
+
+// Label represents a gitee label on an Issue or Pull Request.
+type Label struct {
+	ID           *int64     `json:"id,omitempty"`
+	Name         *string    `json:"name,omitempty"`
+	Color        *string    `json:"color,omitempty"`
+	RepositoryID *int64     `json:"repository_id,omitempty"`
+	URL          *string    `json:"url,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+func (l Label) String() string {
+	return Stringify(l)
+}
+
 // TODO 获取仓库所有任务标签 GET https://gitee.com/api/v5/repos/{owner}/{repo}/labels
@@ -14,6 +14,29 @@
 
 package gitee
 
+import "time"
+
+// Milestone represents a gitee repository milestone.
+type Milestone struct {
+	ID           *int64     `json:"id,omitempty"`
+	URL          *string    `json:"url,omitempty"`
+	HTMLURL      *string    `json:"html_url,omitempty"`
+	Number       *int       `json:"number,omitempty"`
+	RepositoryID *int64     `json:"repository_id,omitempty"`
+	State        *string    `json:"state,omitempty"`
+	Title        *string    `json:"title,omitempty"`
+	Description  *string    `json:"description,omitempty"`
+	OpenIssues   *int       `json:"open_issues,omitempty"`
+	ClosedIssues *int       `json:"closed_issues,omitempty"`
+	DueOn        *string    `json:"due_on,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+func (m Milestone) String() string {
+	return Stringify(m)
+}
+
 // TODO 获取仓库所有里程碑 GET https://gitee.com/api/v5/repos/{owner}/{repo}/milestones
 
 // TODO 创建仓库里程碑 POST https://gitee.com/api/v5/repos/{owner}/{repo}/milestones
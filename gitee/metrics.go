@@ -0,0 +1,175 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PrometheusObserver is an Observer counting the calls of a Client and
+// exposing the counters in the Prometheus text format, without depending on
+// the Prometheus client library.
+//
+// Example usage:
+//
+//  metrics := gitee.NewPrometheusObserver("gitee")
+//  client.Observer = metrics
+//  http.Handle("/metrics", metrics)
+type PrometheusObserver struct {
+	namespace string
+
+	mu      sync.Mutex
+	series  map[metricKey]*metricValues
+	retries map[metricKey]int64 // 不区分状态码
+}
+
+// metricKey are the labels of a series.
+type metricKey struct {
+	method, path, status string
+}
+
+// metricValues are the counters of a series.
+type metricValues struct {
+	requests      int64
+	seconds       float64
+	requestBytes  int64
+	responseBytes int64
+}
+
+// NewPrometheusObserver returns a PrometheusObserver whose metric names
+// start with namespace, e.g. "gitee_requests_total".
+func NewPrometheusObserver(namespace string) *PrometheusObserver {
+	return &PrometheusObserver{
+		namespace: namespace,
+		series:    make(map[metricKey]*metricValues),
+		retries:   make(map[metricKey]int64),
+	}
+}
+
+// ObserveRequest implements the Observer interface.
+func (p *PrometheusObserver) ObserveRequest(ctx context.Context, e *RequestEvent) {
+	status := strconv.Itoa(e.StatusCode)
+	if e.Err != nil {
+		status = "error"
+	}
+	key := metricKey{method: e.Method, path: e.Path, status: status}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	v := p.series[key]
+	if v == nil {
+		v = &metricValues{}
+		p.series[key] = v
+	}
+	v.requests++
+	v.seconds += e.Duration.Seconds()
+	if e.RequestBytes > 0 {
+		v.requestBytes += e.RequestBytes
+	}
+	v.responseBytes += e.ResponseBytes
+	if e.Attempts > 1 {
+		p.retries[metricKey{method: e.Method, path: e.Path}] += int64(e.Attempts - 1)
+	}
+}
+
+// WriteTo writes the counters in the Prometheus text format to w.
+func (p *PrometheusObserver) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	keys := make([]metricKey, 0, len(p.series))
+	values := make(map[metricKey]metricValues, len(p.series))
+	for k, v := range p.series {
+		keys = append(keys, k)
+		values[k] = *v
+	}
+	retryKeys := make([]metricKey, 0, len(p.retries))
+	retries := make(map[metricKey]int64, len(p.retries))
+	for k, v := range p.retries {
+		retryKeys = append(retryKeys, k)
+		retries[k] = v
+	}
+	p.mu.Unlock()
+	sortMetricKeys(keys)
+	sortMetricKeys(retryKeys)
+
+	var b strings.Builder
+	metric := func(name, typ, help string, keys []metricKey, value func(metricKey) string) {
+		name = p.namespace + "_" + name
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+		suffix := ""
+		if typ == "summary" {
+			suffix = "_sum"
+		}
+		for _, k := range keys {
+			fmt.Fprintf(&b, "%s%s{%s} %s\n", name, suffix, k.labels(), value(k))
+		}
+	}
+
+	metric("requests_total", "counter", "Number of API calls.", keys, func(k metricKey) string {
+		return strconv.FormatInt(values[k].requests, 10)
+	})
+	metric("request_duration_seconds", "summary", "Duration of API calls in seconds.", keys, func(k metricKey) string {
+		return strconv.FormatFloat(values[k].seconds, 'g', -1, 64)
+	})
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s_request_duration_seconds_count{%s} %d\n", p.namespace, k.labels(), values[k].requests)
+	}
+	metric("request_bytes_total", "counter", "Bytes of API request bodies.", keys, func(k metricKey) string {
+		return strconv.FormatInt(values[k].requestBytes, 10)
+	})
+	metric("response_bytes_total", "counter", "Bytes of API response bodies.", keys, func(k metricKey) string {
+		return strconv.FormatInt(values[k].responseBytes, 10)
+	})
+	metric("retries_total", "counter", "Number of retried API requests.", retryKeys, func(k metricKey) string {
+		return strconv.FormatInt(retries[k], 10)
+	})
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves the counters in the Prometheus text format.
+func (p *PrometheusObserver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+func (k metricKey) labels() string {
+	labels := fmt.Sprintf("method=%s,path=%s", strconv.Quote(k.method), strconv.Quote(k.path))
+	if k.status != "" {
+		labels += ",status=" + strconv.Quote(k.status)
+	}
+	return labels
+}
+
+func sortMetricKeys(keys []metricKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+}
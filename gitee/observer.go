@@ -0,0 +1,189 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RequestEvent describes a call made by Client, for logging and metrics.
+// Tokens are redacted from URL and Err.
+type RequestEvent struct {
+	Method string
+	URL    string // full URL, access_token and client_secret redacted
+
+	// Path is the API path with the parameters replaced by placeholders,
+	// e.g. "repos/{owner}/{repo}/pulls/{number}", so it can be used as a
+	// metrics label.
+	Path string
+
+	StatusCode    int           // 0 if no response was received
+	Duration      time.Duration // until the response body was closed
+	Attempts      int           // 1 plus the number of retries
+	RequestBytes  int64         // size of the request body, -1 if unknown
+	ResponseBytes int64         // bytes of the response body read by the caller
+	Err           error         // network error, nil for any HTTP response
+}
+
+// Observer is told about the calls made by Client, once the response body
+// was closed or the call failed. Implementations must be safe for
+// concurrent use and should not block.
+type Observer interface {
+	ObserveRequest(ctx context.Context, e *RequestEvent)
+}
+
+// ObserverFunc is an adapter allowing an ordinary function to be used as
+// an Observer.
+type ObserverFunc func(ctx context.Context, e *RequestEvent)
+
+// ObserveRequest calls f(ctx, e).
+func (f ObserverFunc) ObserveRequest(ctx context.Context, e *RequestEvent) {
+	f(ctx, e)
+}
+
+// observe reports a call to c.Observer, if set.
+func (c *Client) observe(ctx context.Context, req *http.Request, resp *http.Response, start time.Time, attempts int, n int64, err error) {
+	if c.Observer == nil {
+		return
+	}
+
+	e := &RequestEvent{
+		Method:        req.Method,
+		URL:           sanitizeURL(req.URL).String(),
+		Path:          pathTemplate(strings.TrimPrefix(req.URL.Path, c.BaseURL.Path)),
+		Duration:      time.Since(start),
+		Attempts:      attempts,
+		RequestBytes:  req.ContentLength,
+		ResponseBytes: n,
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		e.RequestBytes = 0
+	}
+	if resp != nil {
+		e.StatusCode = resp.StatusCode
+	}
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			if u, perr := url.Parse(uerr.URL); perr == nil {
+				err = &url.Error{Op: uerr.Op, URL: sanitizeURL(u).String(), Err: uerr.Err}
+			}
+		}
+		e.Err = err
+	}
+	c.Observer.ObserveRequest(ctx, e)
+}
+
+// pathParams maps the API path segments followed by parameters to the
+// placeholders of those parameters.
+var pathParams = map[string][]string{
+	"repos":         {"{owner}", "{repo}"},
+	"users":         {"{user}"},
+	"orgs":          {"{org}"},
+	"enterprises":   {"{enterprise}"},
+	"members":       {"{user}"}, // enterprises/{enterprise}/members/{user}
+	"branches":      {"{branch}"},
+	"commits":       {"{sha}"},
+	"trees":         {"{sha}"},
+	"blobs":         {"{sha}"},
+	"tags":          {"{tag}"},
+	"issues":        {"{number}"},
+	"pulls":         {"{number}"},
+	"labels":        {"{name}"},
+	"collaborators": {"{user}"},
+	"memberships":   {"{user}"},
+	"gists":         {"{id}"},
+	"gitignore":     {"{name}"},
+	"templates":     {"{name}"},
+	"licenses":      {"{license}"},
+	"namespaces":    {"{path}"},
+	"networks":      {"{owner}", "{repo}"},
+	"starred":       {"{owner}", "{repo}"}, // user/starred/{owner}/{repo}
+	"subscriptions": {"{owner}", "{repo}"}, // user/subscriptions/{owner}/{repo}
+}
+
+// pathWords are the API path segments that are never parameters, e.g. the
+// "comments" in "issues/comments/{id}".
+var pathWords = map[string]bool{
+	"comments": true, "labels": true, "branches": true, "commits": true, "contents": true,
+	"hooks": true, "keys": true, "pulls": true, "issues": true, "releases": true, "tags": true,
+	"forks": true, "events": true, "members": true, "repos": true, "orgs": true, "latest": true, "merge": true,
+	"files": true, "operate_logs": true, "setting": true, "protection": true, "starred": true,
+	"subscriptions": true, "followers": true, "following": true, "stargazers": true,
+	"subscribers": true, "notifications": true, "threads": true, "messages": true,
+	"collaborators": true, "milestones": true, "contributors": true, "raw": true, "tests": true,
+}
+
+var numericSegment = regexp.MustCompile(`^[0-9]+$`)
+
+// pathTemplate replaces the parameters of an API path like
+// "repos/mamh/go-gitee/pulls/1" by placeholders, giving
+// "repos/{owner}/{repo}/pulls/{number}".
+func pathTemplate(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < len(segments); i++ {
+		seg := segments[i]
+		switch {
+		case seg == "contents" || seg == "raw":
+			// 文件路径里面可以有 /, 后面的都是路径
+			if i+1 < len(segments) {
+				segments = append(segments[:i+1], "{path}")
+			}
+			return strings.Join(segments, "/")
+		case seg == "branches" && i+1 < len(segments):
+			// 分支名里面也可以有 /, 只有最后的 protection 和 setting 不是分支名
+			rest := []string{"{branch}"}
+			if last := segments[len(segments)-1]; len(segments) > i+2 && (last == "protection" || last == "setting") {
+				rest = append(rest, last)
+			}
+			return strings.Join(append(segments[:i+1], rest...), "/")
+		case pathParams[seg] != nil:
+			for _, param := range pathParams[seg] {
+				if i+1 >= len(segments) || pathWords[segments[i+1]] {
+					break
+				}
+				i++
+				segments[i] = param
+			}
+		case numericSegment.MatchString(seg):
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// LogObserver returns an Observer writing one line per call to logger, or to
+// the standard logger if logger is nil.
+func LogObserver(logger *log.Logger) Observer {
+	if logger == nil {
+		logger = log.New(log.Writer(), log.Prefix(), log.Flags())
+	}
+	return ObserverFunc(func(ctx context.Context, e *RequestEvent) {
+		status := http.StatusText(e.StatusCode)
+		if e.Err != nil {
+			status = e.Err.Error()
+		}
+		logger.Printf("gitee: %s %s %d %s in %v, %d attempt(s), %d/%d bytes",
+			e.Method, e.URL, e.StatusCode, status, e.Duration.Round(time.Millisecond),
+			e.Attempts, e.RequestBytes, e.ResponseBytes)
+	})
+}
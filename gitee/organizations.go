@@ -24,16 +24,27 @@ import (
 type OrganizationsService service
 
 type Organization struct {
-	ID          *int64  `json:"id,omitempty"`
-	Login       *string `json:"login,omitempty"`
-	Name        *string `json:"name,omitempty"`
-	URL         *string `json:"url,omitempty"`
-	AvatarURL   *string `json:"avatar_url,omitempty"`
-	ReposURL    *string `json:"repos_url,omitempty"`
-	EventsURL   *string `json:"events_url,omitempty"`
-	MembersURL  *string `json:"members_url,omitempty"`
-	Description *string `json:"description,omitempty"`
-	FollowCount *int64  `json:"follow_count,omitempty"`
+	ID           *int64     `json:"id,omitempty"`
+	Login        *string    `json:"login,omitempty"`
+	Name         *string    `json:"name,omitempty"`
+	URL          *string    `json:"url,omitempty"`
+	AvatarURL    *string    `json:"avatar_url,omitempty"`
+	ReposURL     *string    `json:"repos_url,omitempty"`
+	EventsURL    *string    `json:"events_url,omitempty"`
+	MembersURL   *string    `json:"members_url,omitempty"`
+	Description  *string    `json:"description,omitempty"`
+	FollowCount  *int64     `json:"follow_count,omitempty"`
+	CreatedAt    *Timestamp `json:"created_at,omitempty"`
+	Type         *string    `json:"type,omitempty"`
+	Location     *string    `json:"location,omitempty"`      // 组织所在地
+	Email        *string    `json:"email,omitempty"`         // 组织公开的邮箱地址
+	HTMLURL      *string    `json:"html_url,omitempty"`      // 组织站点
+	Public       *bool      `json:"public,omitempty"`        // 是否公开
+	Enterprise   *string    `json:"enterprise,omitempty"`    // 所属企业
+	Members      *int       `json:"members,omitempty"`       // 成员数量
+	PublicRepos  *int       `json:"public_repos,omitempty"`  // 公开仓库数量
+	PrivateRepos *int       `json:"private_repos,omitempty"` // 私有仓库数量
+	Owner        *User      `json:"owner,omitempty"`
 }
 
 func (o Organization) String() string {
@@ -164,3 +175,128 @@ func (s *OrganizationsService) EditOrgMembership(ctx context.Context, org string
 
 	return m, resp, nil
 }
+
+// Get fetches an organization by name.
+//
+//  获取一个组织 GET https://gitee.com/api/v5/orgs/{org}
+func (s *OrganizationsService) Get(ctx context.Context, org string) (*Organization, *Response, error) {
+	u := fmt.Sprintf("orgs/%v", org)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	organization := new(Organization)
+	resp, err := s.client.Do(ctx, req, organization)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return organization, resp, nil
+}
+
+type OrganizationEditRequest struct {
+	Email       *string `json:"email,omitempty"`       // 组织公开的邮箱地址
+	Location    *string `json:"location,omitempty"`    // 组织所在地
+	Name        *string `json:"name,omitempty"`        // 组织名称
+	Description *string `json:"description,omitempty"` // 组织简介
+	HTMLURL     *string `json:"html_url,omitempty"`    // 组织站点
+}
+
+// Edit an organization.
+//
+//  更新授权用户所管理的组织资料 PATCH https://gitee.com/api/v5/orgs/{org}
+func (s *OrganizationsService) Edit(ctx context.Context, org string, orgReq *OrganizationEditRequest) (*Organization, *Response, error) {
+	u := fmt.Sprintf("orgs/%v", org)
+	req, err := s.client.NewRequest("PATCH", u, orgReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	o := new(Organization)
+	resp, err := s.client.Do(ctx, req, o)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return o, resp, nil
+}
+
+type ListMembersOptions struct {
+	Role string `url:"role,omitempty"` // 根据角色筛选成员: all(默认), admin, member
+	ListOptions
+}
+
+// ListMembers lists the members for an organization.
+//
+//  列出一个组织的所有成员 GET https://gitee.com/api/v5/orgs/{org}/members
+func (s *OrganizationsService) ListMembers(ctx context.Context, org string, opts *ListMembersOptions) ([]*User, *Response, error) {
+	u := fmt.Sprintf("orgs/%v/members", org)
+	u, err := addOptions(u, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var members []*User
+	resp, err := s.client.Do(ctx, req, &members)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return members, resp, nil
+}
+
+type MembershipAddRequest struct {
+	Role *string `json:"role,omitempty"` // 设置用户在组织的角色: admin, member。默认: member
+}
+
+// AddOrgMembership invites a user to an organization, or updates the role
+// of an existing member.
+//
+//  增加或更新授权用户所管理组织的成员 PUT https://gitee.com/api/v5/orgs/{org}/memberships/{username}
+func (s *OrganizationsService) AddOrgMembership(ctx context.Context, org, user string, membership *MembershipAddRequest) (*Membership, *Response, error) {
+	u := fmt.Sprintf("orgs/%v/memberships/%v", org, user)
+	req, err := s.client.NewRequest("PUT", u, membership)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := new(Membership)
+	resp, err := s.client.Do(ctx, req, m)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return m, resp, nil
+}
+
+// RemoveOrgMembership removes a user from an organization.
+//
+//  移除授权用户所管理组织中的成员 DELETE https://gitee.com/api/v5/orgs/{org}/memberships/{username}
+func (s *OrganizationsService) RemoveOrgMembership(ctx context.Context, org, user string) (*Response, error) {
+	u := fmt.Sprintf("orgs/%v/memberships/%v", org, user)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// Leave makes the authenticated user leave an organization.
+//
+//  退出一个组织 DELETE https://gitee.com/api/v5/user/memberships/orgs/{org}
+func (s *OrganizationsService) Leave(ctx context.Context, org string) (*Response, error) {
+	u := fmt.Sprintf("user/memberships/orgs/%v", org)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
@@ -0,0 +1,515 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrStopPagination can be returned by the fetch function given to Paginate
+// to stop after the current page without reporting an error.
+var ErrStopPagination = errors.New("stop pagination")
+
+// Paginate calls fetch once per page, starting at opts.Page, until the last
+// page was fetched, ctx is done or fetch returns an error. opts must be the
+// ListOptions of the options struct fetch passes to the list method, Paginate
+// advances opts.Page between the calls. The next page is taken from the Link
+// header, or from the Total_page header when gitee does not send a Link.
+// A fetch returning a nil Response and no error ends the pagination.
+//
+// Example usage:
+//
+//  opts := &gitee.CommitsListOptions{SHA: "master"}
+//  err := gitee.Paginate(ctx, &opts.ListOptions, func(ctx context.Context) (*gitee.Response, error) {
+//    commits, resp, err := client.Repositories.ListCommits(ctx, "owner", "repo", opts)
+//    for _, c := range commits {
+//      if *c.SHA == stopAt {
+//        return resp, gitee.ErrStopPagination
+//      }
+//      ...
+//    }
+//    return resp, err
+//  })
+func Paginate(ctx context.Context, opts *ListOptions, fetch func(ctx context.Context) (*Response, error)) error {
+	if ctx == nil {
+		return errNonNilContext
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		resp, err := fetch(ctx)
+		if errors.Is(err, ErrStopPagination) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if resp == nil { // 没有可以取的了
+			return nil
+		}
+
+		next := resp.NextPage
+		if next == 0 {
+			page := opts.Page
+			if page == 0 {
+				page = 1
+			}
+			if page < resp.TotalPage {
+				next = page + 1
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		opts.Page = next
+	}
+}
+
+// DefaultPageConcurrency is the number of pages FetchPages fetches at the
+// same time when no concurrency is given.
+const DefaultPageConcurrency = 4
+
+// PageError is returned by FetchPages when some pages failed. The pages that
+// did not fail were still passed to fetch successfully.
+type PageError struct {
+	Errors map[int]error // page -> error
+}
+
+// Pages returns the failed pages in ascending order.
+func (e *PageError) Pages() []int {
+	pages := make([]int, 0, len(e.Errors))
+	for page := range e.Errors {
+		pages = append(pages, page)
+	}
+	sort.Ints(pages)
+	return pages
+}
+
+func (e *PageError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to fetch %d page(s):", len(e.Errors))
+	for _, page := range e.Pages() {
+		fmt.Fprintf(&b, " page %d: %v;", page, e.Errors[page])
+	}
+	return strings.TrimSuffix(b.String(), ";")
+}
+
+// Unwrap returns the error of the first failed page, so errors.Is and
+// errors.As work for e.g. RateLimitError.
+func (e *PageError) Unwrap() error {
+	pages := e.Pages()
+	if len(pages) == 0 {
+		return nil
+	}
+	return e.Errors[pages[0]]
+}
+
+// FetchPages fetches the first page, starting at opts.Page, and then the
+// remaining pages up to the Total_page header with at most concurrency calls
+// of fetch at the same time. fetch gets the page it has to fetch and is
+// responsible for storing its results by page, so that callers can put them
+// back into page order; calls for different pages may run concurrently.
+//
+// An error of the first page is returned as is. Errors of the other pages do
+// not stop the remaining pages and are returned together as *PageError.
+// When the response of the first page has no Total_page but a next page,
+// the remaining pages are fetched one by one like Paginate does. A nil
+// Response of the first page means there is nothing more to fetch.
+func FetchPages(ctx context.Context, opts *ListOptions, concurrency int, fetch func(ctx context.Context, page int) (*Response, error)) error {
+	if ctx == nil {
+		return errNonNilContext
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultPageConcurrency
+	}
+
+	first := 1
+	if opts != nil && opts.Page > 0 {
+		first = opts.Page
+	}
+	resp, err := fetch(ctx, first)
+	if err != nil || resp == nil {
+		return err
+	}
+
+	last := resp.TotalPage
+	if last == 0 {
+		last = resp.LastPage
+	}
+	if last == 0 && resp.NextPage != 0 {
+		o := ListOptions{Page: resp.NextPage}
+		return Paginate(ctx, &o, func(ctx context.Context) (*Response, error) {
+			return fetch(ctx, o.Page)
+		})
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[int]error)
+		sem  = make(chan struct{}, concurrency)
+	)
+loop:
+	for page := first + 1; page <= last; page++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			for p := page; p <= last; p++ {
+				errs[p] = ctx.Err()
+			}
+			mu.Unlock()
+			break loop
+		}
+
+		wg.Add(1)
+		go func(page int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if _, err := fetch(ctx, page); err != nil {
+				mu.Lock()
+				errs[page] = err
+				mu.Unlock()
+			}
+		}(page)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &PageError{Errors: errs}
+	}
+	return nil
+}
+
+// ListAll lists all repositories for a user, following all pages.
+// See RepositoriesService.List.
+func (s *RepositoriesService) ListAll(ctx context.Context, user string, opts *RepositoryListOptions) ([]*Repository, error) {
+	o := RepositoryListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*Repository
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		repos, resp, err := s.List(ctx, user, &o)
+		all = append(all, repos...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllOrganizations lists all repositories of an organization, following
+// all pages. See RepositoriesService.ListOrganizations.
+func (s *RepositoriesService) ListAllOrganizations(ctx context.Context, org string, opts *RepositoryListOptions) ([]*Repository, error) {
+	o := RepositoryListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*Repository
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		repos, resp, err := s.ListOrganizations(ctx, org, &o)
+		all = append(all, repos...)
+		return resp, err
+	})
+	return all, err
+}
+
+// FetchAllOrganizations lists all repositories of an organization like
+// ListAllOrganizations, but fetches the pages after the first one with up to
+// concurrency requests at the same time. The repositories are returned in page
+// order; on a *PageError the repositories of the pages that did not fail are
+// returned as well.
+func (s *RepositoriesService) FetchAllOrganizations(ctx context.Context, org string, opts *RepositoryListOptions, concurrency int) ([]*Repository, error) {
+	o := RepositoryListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var mu sync.Mutex
+	pages := make(map[int][]*Repository)
+	err := FetchPages(ctx, &o.ListOptions, concurrency, func(ctx context.Context, page int) (*Response, error) {
+		po := o
+		po.Page = page
+		repos, resp, err := s.ListOrganizations(ctx, org, &po)
+		mu.Lock()
+		pages[page] = repos
+		mu.Unlock()
+		return resp, err
+	})
+	return flattenRepositoryPages(pages), err
+}
+
+func flattenRepositoryPages(pages map[int][]*Repository) []*Repository {
+	keys := make([]int, 0, len(pages))
+	for page := range pages {
+		keys = append(keys, page)
+	}
+	sort.Ints(keys)
+
+	var all []*Repository
+	for _, page := range keys {
+		all = append(all, pages[page]...)
+	}
+	return all
+}
+
+// ListAllEnterprises lists all repositories of an enterprise, following all
+// pages. See RepositoriesService.ListEnterprises.
+func (s *RepositoriesService) ListAllEnterprises(ctx context.Context, enterprise string, opts *RepositoryListOptions) ([]*Repository, error) {
+	o := RepositoryListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*Repository
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		repos, resp, err := s.ListEnterprises(ctx, enterprise, &o)
+		all = append(all, repos...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllCommits lists all commits of a repository, following all pages.
+// See RepositoriesService.ListCommits.
+func (s *RepositoriesService) ListAllCommits(ctx context.Context, owner, repo string, opts *CommitsListOptions) ([]*RepositoryCommit, error) {
+	o := CommitsListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*RepositoryCommit
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		commits, resp, err := s.ListCommits(ctx, owner, repo, &o)
+		all = append(all, commits...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllForks lists all forks of a repository, following all pages.
+// See RepositoriesService.ListForks.
+func (s *RepositoriesService) ListAllForks(ctx context.Context, owner, repo string, opts *RepositoryListForksOptions) ([]*Repository, error) {
+	o := RepositoryListForksOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*Repository
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		forks, resp, err := s.ListForks(ctx, owner, repo, &o)
+		all = append(all, forks...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllComments lists all commit comments of a repository, following all
+// pages. See RepositoriesService.ListComments.
+func (s *RepositoriesService) ListAllComments(ctx context.Context, owner, repo string, opts *CommentsListOptions) ([]*RepositoryComment, error) {
+	o := CommentsListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*RepositoryComment
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		comments, resp, err := s.ListComments(ctx, owner, repo, &o)
+		all = append(all, comments...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllContributors lists all contributors of a repository, following all
+// pages. See RepositoriesService.ListContributors.
+func (s *RepositoriesService) ListAllContributors(ctx context.Context, owner, repo string, opts *ContributorListOptions) ([]*Contributor, error) {
+	o := ContributorListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*Contributor
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		contributors, resp, err := s.ListContributors(ctx, owner, repo, &o)
+		all = append(all, contributors...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllReleases lists all releases of a repository, following all pages.
+// See RepositoriesService.ListReleases.
+func (s *RepositoriesService) ListAllReleases(ctx context.Context, owner, repo string, opts *RepositoryReleaseListOptions) ([]*RepositoryRelease, error) {
+	o := RepositoryReleaseListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*RepositoryRelease
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		releases, resp, err := s.ListReleases(ctx, owner, repo, &o)
+		all = append(all, releases...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllCollaborators lists all collaborators of a repository, following all
+// pages. See RepositoriesService.ListCollaborators.
+func (s *RepositoriesService) ListAllCollaborators(ctx context.Context, owner, repo string, opts *ListOptions) ([]*User, error) {
+	o := ListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*User
+	err := Paginate(ctx, &o, func(ctx context.Context) (*Response, error) {
+		users, resp, err := s.ListCollaborators(ctx, owner, repo, &o)
+		all = append(all, users...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllHooks lists all hooks of a repository, following all pages.
+// See RepositoriesService.ListHooks.
+func (s *RepositoriesService) ListAllHooks(ctx context.Context, owner, repo string, opts *ListOptions) ([]*Hook, error) {
+	o := ListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*Hook
+	err := Paginate(ctx, &o, func(ctx context.Context) (*Response, error) {
+		hooks, resp, err := s.ListHooks(ctx, owner, repo, &o)
+		all = append(all, hooks...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAll lists all pull requests of a repository, following all pages.
+// See PullRequestsService.List.
+func (s *PullRequestsService) ListAll(ctx context.Context, owner, repo string, opts *PullRequestListOptions) ([]*PullRequest, error) {
+	o := PullRequestListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*PullRequest
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		pulls, resp, err := s.List(ctx, owner, repo, &o)
+		all = append(all, pulls...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAll lists all organizations of a user, following all pages.
+// See OrganizationsService.List.
+func (s *OrganizationsService) ListAll(ctx context.Context, user string, opts *OrganizationListOptions) ([]*Organization, error) {
+	o := OrganizationListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*Organization
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		orgs, resp, err := s.List(ctx, user, &o)
+		all = append(all, orgs...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllMembers lists all members of an organization, following all pages.
+// See OrganizationsService.ListMembers.
+func (s *OrganizationsService) ListAllMembers(ctx context.Context, org string, opts *ListMembersOptions) ([]*User, error) {
+	o := ListMembersOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*User
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		members, resp, err := s.ListMembers(ctx, org, &o)
+		all = append(all, members...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllFollowers lists all followers of a user, following all pages.
+// See UsersService.ListFollowers.
+func (s *UsersService) ListAllFollowers(ctx context.Context, user string, opts *ListOptions) ([]*User, error) {
+	o := ListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*User
+	err := Paginate(ctx, &o, func(ctx context.Context) (*Response, error) {
+		users, resp, err := s.ListFollowers(ctx, user, &o)
+		all = append(all, users...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllStargazers lists all users who starred a repository, following all
+// pages. See ActivityService.ListStargazers.
+func (s *ActivityService) ListAllStargazers(ctx context.Context, owner, repo string, opts *ListOptions) ([]*User, error) {
+	o := ListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*User
+	err := Paginate(ctx, &o, func(ctx context.Context) (*Response, error) {
+		users, resp, err := s.ListStargazers(ctx, owner, repo, &o)
+		all = append(all, users...)
+		return resp, err
+	})
+	return all, err
+}
+
+// ListAllNotifications lists all notifications of the authenticated user,
+// following all pages. See ActivityService.ListNotifications.
+func (s *ActivityService) ListAllNotifications(ctx context.Context, opts *NotificationListOptions) ([]*Notification, error) {
+	o := NotificationListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	var all []*Notification
+	err := Paginate(ctx, &o.ListOptions, func(ctx context.Context) (*Response, error) {
+		notifications, resp, err := s.ListNotifications(ctx, &o)
+		all = append(all, notifications...)
+		return resp, err
+	})
+	return all, err
+}
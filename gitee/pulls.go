@@ -17,6 +17,7 @@ package gitee
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // PullRequestsService handles communication with the pull request related
@@ -25,11 +26,38 @@ type PullRequestsService service
 
 // PullRequest represents a GitHub pull request on a repository.
 type PullRequest struct {
-	ID     *int64  `json:"id,omitempty"`
-	Number *int    `json:"number,omitempty"`
-	State  *string `json:"state,omitempty"`
-	Locked *bool   `json:"locked,omitempty"`
-	Title  *string `json:"title,omitempty"`
+	ID        *int64             `json:"id,omitempty"`
+	Number    *int               `json:"number,omitempty"`
+	State     *string            `json:"state,omitempty"`
+	Locked    *bool              `json:"locked,omitempty"`
+	Title     *string            `json:"title,omitempty"`
+	Body      *string            `json:"body,omitempty"`
+	URL       *string            `json:"url,omitempty"`
+	HTMLURL   *string            `json:"html_url,omitempty"`
+	DiffURL   *string            `json:"diff_url,omitempty"`
+	PatchURL  *string            `json:"patch_url,omitempty"`
+	User      *User              `json:"user,omitempty"`
+	Assignees []*User            `json:"assignees,omitempty"`
+	Testers   []*User            `json:"testers,omitempty"`
+	Labels    []*Label           `json:"labels,omitempty"`
+	Milestone *Milestone         `json:"milestone,omitempty"`
+	Head      *PullRequestBranch `json:"head,omitempty"`
+	Base      *PullRequestBranch `json:"base,omitempty"`
+	Mergeable *bool              `json:"mergeable,omitempty"`
+	Draft     *bool              `json:"draft,omitempty"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+	UpdatedAt *time.Time         `json:"updated_at,omitempty"`
+	ClosedAt  *time.Time         `json:"closed_at,omitempty"`
+	MergedAt  *time.Time         `json:"merged_at,omitempty"`
+}
+
+// PullRequestBranch represents a base or head branch in a gitee pull request.
+type PullRequestBranch struct {
+	Label *string     `json:"label,omitempty"`
+	Ref   *string     `json:"ref,omitempty"`
+	SHA   *string     `json:"sha,omitempty"`
+	User  *User       `json:"user,omitempty"`
+	Repo  *Repository `json:"repo,omitempty"`
 }
 
 func (p PullRequest) String() string {
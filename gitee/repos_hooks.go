@@ -40,6 +40,7 @@ func (h Hook) String() string {
 
 // ListHooks lists all Hooks for the specified repository.
 //
+//  列出仓库的WebHooks GET https://gitee.com/api/v5/repos/{owner}/{repo}/hooks
 func (s *RepositoriesService) ListHooks(ctx context.Context, owner, repo string, opts *ListOptions) ([]*Hook, *Response, error) {
 	u := fmt.Sprintf("repos/%v/%v/hooks", owner, repo)
 	u, err := addOptions(u, opts)
@@ -60,3 +61,104 @@ func (s *RepositoriesService) ListHooks(ctx context.Context, owner, repo string,
 
 	return hooks, resp, nil
 }
+
+// WebHook 的加密类型, 用于 HookRequest.EncryptionType
+const (
+	HookEncryptionPassword = 0 // 密码, 在请求头 X-Gitee-Token 里面原样带上密码
+	HookEncryptionSign     = 1 // 签名密钥, 在请求头 X-Gitee-Token 里面带上签名
+)
+
+// HookRequest is used to create or edit a repository hook.
+type HookRequest struct {
+	URL                 *string `json:"url,omitempty"`                   // 远程HTTP URL
+	EncryptionType      *int    `json:"encryption_type,omitempty"`       // 加密类型: 0: 密码, 1: 签名密钥
+	Password            *string `json:"password,omitempty"`              // 请求URL时会带上该密码或者签名密钥，防止URL被恶意请求
+	PushEvents          *bool   `json:"push_events,omitempty"`           // Push代码到仓库
+	TagPushEvents       *bool   `json:"tag_push_events,omitempty"`       // 提交Tag到仓库
+	IssuesEvents        *bool   `json:"issues_events,omitempty"`         // 创建/关闭Issue
+	NoteEvents          *bool   `json:"note_events,omitempty"`           // 评论了Issue/代码等等
+	MergeRequestsEvents *bool   `json:"merge_requests_events,omitempty"` // 合并请求和合并后
+}
+
+// GetHook returns a single specified Hook.
+//
+//  获取仓库单个WebHook GET https://gitee.com/api/v5/repos/{owner}/{repo}/hooks/{id}
+func (s *RepositoriesService) GetHook(ctx context.Context, owner, repo string, id int64) (*Hook, *Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/hooks/%d", owner, repo, id)
+	req, err := s.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := new(Hook)
+	resp, err := s.client.Do(ctx, req, h)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return h, resp, nil
+}
+
+// CreateHook creates a Hook for the specified repository.
+//
+//  创建一个仓库WebHook POST https://gitee.com/api/v5/repos/{owner}/{repo}/hooks
+func (s *RepositoriesService) CreateHook(ctx context.Context, owner, repo string, hook *HookRequest) (*Hook, *Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/hooks", owner, repo)
+	req, err := s.client.NewRequest("POST", u, hook)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := new(Hook)
+	resp, err := s.client.Do(ctx, req, h)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return h, resp, nil
+}
+
+// EditHook updates a specified Hook.
+//
+//  更新一个仓库WebHook PATCH https://gitee.com/api/v5/repos/{owner}/{repo}/hooks/{id}
+func (s *RepositoriesService) EditHook(ctx context.Context, owner, repo string, id int64, hook *HookRequest) (*Hook, *Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/hooks/%d", owner, repo, id)
+	req, err := s.client.NewRequest("PATCH", u, hook)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := new(Hook)
+	resp, err := s.client.Do(ctx, req, h)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return h, resp, nil
+}
+
+// DeleteHook deletes a specified Hook.
+//
+//  删除一个仓库WebHook DELETE https://gitee.com/api/v5/repos/{owner}/{repo}/hooks/{id}
+func (s *RepositoriesService) DeleteHook(ctx context.Context, owner, repo string, id int64) (*Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/hooks/%d", owner, repo, id)
+	req, err := s.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// TestHook triggers a test Hook by gitee.
+//
+//  测试WebHook是否发送成功 POST https://gitee.com/api/v5/repos/{owner}/{repo}/hooks/{id}/tests
+func (s *RepositoriesService) TestHook(ctx context.Context, owner, repo string, id int64) (*Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/hooks/%d/tests", owner, repo, id)
+	req, err := s.client.NewRequest("POST", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
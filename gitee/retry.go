@@ -0,0 +1,150 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how Client retries requests that failed with a
+// transient error: a network error, 429 Too Many Requests, or 500, 502, 503
+// and 504. Retries are opt-in, set Client.RetryPolicy or attach a policy to a
+// single call with WithRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry, it doubles with every
+	// further retry up to MaxBackoff. The actual wait is randomly picked
+	// between half and all of it, so that clients do not retry in lockstep.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent also retries POST and PATCH requests. By default only
+	// GET, HEAD, OPTIONS, PUT and DELETE are retried, since retrying a POST
+	// that reached gitee might e.g. create an issue twice.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a policy making up to 4 attempts, waiting about
+// 1s, 2s and 4s between them.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  time.Second,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+type retryPolicyKey struct{}
+
+// WithRetryPolicy returns a copy of ctx overriding Client.RetryPolicy for the
+// calls made with it. Pass &RetryPolicy{} to disable retries for a call.
+func WithRetryPolicy(ctx context.Context, policy *RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// retryPolicy returns the policy in effect for a call made with ctx.
+func (c *Client) retryPolicy(ctx context.Context) *RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(*RetryPolicy); ok {
+		return policy
+	}
+	return c.RetryPolicy
+}
+
+// shouldRetry reports whether the attempt that got resp, err may be retried.
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+	default:
+		if !p.RetryNonIdempotent {
+			return false
+		}
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false // 请求体不能重放
+	}
+
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the wait before retry number n (starting at 1).
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < n && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// doWithRetry sends req, retrying transient failures according to the
+// policy in effect for ctx. It also returns the number of attempts made.
+func (c *Client) doWithRetry(ctx context.Context, req *http.Request) (*http.Response, int, error) {
+	policy := c.retryPolicy(ctx)
+	for attempt := 1; ; attempt++ {
+		resp, err := c.client.Do(req)
+		if policy == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !policy.shouldRetry(req, resp, err) {
+			return resp, attempt, err
+		}
+
+		wait := policy.backoff(attempt)
+		if resp != nil {
+			if retryAfter := parseRetryAfter(resp.Header.Get(headerRetryAfter)); retryAfter > 0 {
+				if policy.MaxBackoff > 0 && retryAfter > policy.MaxBackoff {
+					return resp, attempt, err // 要等的时间太长了, 交给调用者处理
+				}
+				wait = retryAfter
+			}
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, attempt, err
+			}
+			req.Body = body
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
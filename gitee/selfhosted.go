@@ -0,0 +1,133 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// apiPath is the path of the API below the web root of a gitee deployment.
+const apiPath = "api/v5/"
+
+// NewEnterpriseClient returns a new gitee API client for a self-hosted or
+// private gitee deployment. baseURL may be given with or without scheme,
+// API prefix and trailing slash, e.g. "gitee.example.com",
+// "https://gitee.example.com/" and "https://gitee.example.com/api/v5" all
+// give the BaseURL "https://gitee.example.com/api/v5/" and the WebURL
+// "https://gitee.example.com/". A deployment below a sub-path keeps it.
+//
+// Use NewEnterpriseTransport for custom CA bundles and proxies:
+//
+//  tr, err := gitee.NewEnterpriseTransport(&gitee.EnterpriseTransportOptions{CAFile: "/etc/ssl/corp-ca.pem"})
+//  tp := &gitee.TokenTransport{Token: token, Transport: tr}
+//  client, err := gitee.NewEnterpriseClient("https://gitee.example.com", tp.Client())
+func NewEnterpriseClient(baseURL string, httpClient *http.Client) (*Client, error) {
+	apiURL, webURL, err := parseEnterpriseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewClient(httpClient)
+	c.BaseURL = apiURL
+	c.WebURL = webURL
+	return c, nil
+}
+
+// parseEnterpriseURL returns the API and web URL of a deployment.
+func parseEnterpriseURL(baseURL string) (apiURL, webURL *url.URL, err error) {
+	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "" {
+		return nil, nil, errors.New("gitee: base URL is empty")
+	}
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, nil, fmt.Errorf("gitee: invalid base URL %q", baseURL)
+	}
+	u.RawQuery, u.Fragment = "", ""
+
+	path := strings.TrimSuffix(u.Path, "/") + "/"
+	if strings.HasSuffix(path, "/"+apiPath) {
+		path = strings.TrimSuffix(path, apiPath)
+	}
+	u.Path, u.RawPath = path, ""
+
+	webURL = u
+	apiURL, _ = webURL.Parse(apiPath)
+	return apiURL, webURL, nil
+}
+
+// EnterpriseTransportOptions configures the transport of NewEnterpriseTransport.
+type EnterpriseTransportOptions struct {
+	// CAFile and CAPEM add PEM encoded CA certificates to the system pool,
+	// for deployments using a private CA.
+	CAFile string
+	CAPEM  []byte
+
+	// ProxyURL is the proxy for all requests, e.g. "http://proxy:3128".
+	// Defaults to the HTTP_PROXY / HTTPS_PROXY / NO_PROXY environment.
+	ProxyURL string
+}
+
+// NewEnterpriseTransport returns a transport like http.DefaultTransport
+// trusting the CA certificates and using the proxy of opts. Use it as
+// Transport of TokenTransport or as Base of an oauth2.Transport.
+func NewEnterpriseTransport(opts *EnterpriseTransportOptions) (*http.Transport, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if opts == nil {
+		return tr, nil
+	}
+
+	if opts.CAFile != "" || len(opts.CAPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pem := opts.CAPEM
+		if opts.CAFile != "" {
+			b, err := ioutil.ReadFile(opts.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			pem = append(append(pem, '\n'), b...)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("gitee: no CA certificates found in CAFile or CAPEM")
+		}
+		tr.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	if opts.ProxyURL != "" {
+		proxy, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		tr.Proxy = http.ProxyURL(proxy)
+	}
+	return tr, nil
+}
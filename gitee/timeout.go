@@ -0,0 +1,82 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+type timeoutKey struct{}
+
+// WithTimeout returns a copy of ctx overriding Client.Timeout for the calls
+// made with it. Pass 0 to disable the client's timeout for a call, e.g. for a
+// large download.
+func WithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
+// timeout returns the timeout in effect for a call made with ctx.
+func (c *Client) timeout(ctx context.Context) time.Duration {
+	if timeout, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return c.Timeout
+}
+
+// callContext returns the context of a call made with ctx, limited by the
+// timeout in effect. cancel must be called once the call is done.
+func (c *Client) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout := c.timeout(ctx); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// callBody is the response body of a call. It releases the context of the
+// call and reports the call to the Observer once the caller closed the body.
+// 不能在 BareDo 返回的时候就 cancel, 那时候 body 还没有读.
+type callBody struct {
+	io.ReadCloser
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   func(n int64) // 可以为 nil, n 是读到的字节数
+	n      int64
+	once   sync.Once
+}
+
+// Read returns the context's error instead of the transport's one when the
+// call timed out or was canceled while reading the body.
+func (b *callBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	if err != nil && err != io.EOF && b.ctx.Err() != nil {
+		err = b.ctx.Err()
+	}
+	return n, err
+}
+
+func (b *callBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.cancel()
+		if b.done != nil {
+			b.done(b.n)
+		}
+	})
+	return err
+}
@@ -16,6 +16,7 @@ package gitee
 
 import (
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -32,10 +33,11 @@ func (t Timestamp) String() string {
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
-// Time is expected in RFC3339 or Unix format.
+// Time is expected in RFC3339 or Unix format. gitee 的 webhook 里面 timestamp
+// 是带引号的毫秒数，如 "1576754827988"，这种也按 Unix 格式处理
 func (t *Timestamp) UnmarshalJSON(data []byte) (err error) {
 	str := string(data)
-	i, err := strconv.ParseInt(str, 10, 64)
+	i, err := strconv.ParseInt(strings.Trim(str, `"`), 10, 64)
 	if err == nil {
 		t.Time = time.Unix(i, 0)
 		if t.Time.Year() > 3000 {
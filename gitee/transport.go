@@ -0,0 +1,81 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"errors"
+	"net/http"
+)
+
+// TokenPlacement tells TokenTransport where to put the access token.
+type TokenPlacement int
+
+const (
+	// TokenInHeader sends the token as "Authorization: Bearer <token>".
+	TokenInHeader TokenPlacement = iota
+	// TokenInQuery sends the token as the access_token query parameter, for
+	// proxies that strip the Authorization header.
+	TokenInQuery
+)
+
+// TokenTransport is an http.RoundTripper authenticating requests with a
+// personal access token, either in the Authorization header or in the
+// access_token query parameter gitee accepts as well. The token is redacted
+// from the URLs in the errors of the client.
+//
+// Example usage:
+//
+//  tp := &gitee.TokenTransport{Token: os.Getenv("GITEE_TOKEN"), Placement: gitee.TokenInQuery}
+//  client := gitee.NewClient(tp.Client())
+type TokenTransport struct {
+	Token     string
+	Placement TokenPlacement
+
+	// Transport is the underlying HTTP transport to use when making requests.
+	// It will default to http.DefaultTransport if nil.
+	Transport http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *TokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Token == "" {
+		return nil, errors.New("gitee: TokenTransport has no token")
+	}
+
+	// RoundTrip 不能修改调用方的 req
+	req = req.Clone(req.Context())
+	switch t.Placement {
+	case TokenInQuery:
+		q := req.URL.Query()
+		q.Set("access_token", t.Token)
+		req.URL.RawQuery = q.Encode()
+	default:
+		req.Header.Set("Authorization", "Bearer "+t.Token)
+	}
+	return t.transport().RoundTrip(req)
+}
+
+// Client returns an *http.Client that makes requests authenticated with the
+// token.
+func (t *TokenTransport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+func (t *TokenTransport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
@@ -0,0 +1,331 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HookHandler is an http.Handler that verifies, decodes and dispatches gitee
+// webhook deliveries to the callbacks registered with On, OnPush, OnTagPush,
+// OnIssue, OnNote and OnMergeRequest.
+//
+// Responses:
+//
+//  200 OK                        delivery handled, or a duplicate of one already handled
+//  204 No Content                no callback registered for the event type
+//  400 Bad Request               missing or unknown X-Gitee-Event, or malformed payload
+//  401 Unauthorized              bad password, signature or timestamp
+//  405 Method Not Allowed        request is not a POST
+//  409 Conflict                  a duplicate of a delivery still being handled
+//  413 Request Entity Too Large  payload larger than MaxBodyBytes
+//  500 Internal Server Error     a callback returned an error, logged to ErrorLog
+type HookHandler struct {
+	// Secret is the password or sign key configured on the hook. If empty,
+	// deliveries are not authenticated.
+	Secret []byte
+
+	// MaxBodyBytes limits the size of a delivery. Zero means no limit.
+	MaxBodyBytes int64
+
+	// DedupWindow is how long a delivery ID is remembered to drop duplicate
+	// deliveries. Zero disables de-duplication.
+	DedupWindow time.Duration
+
+	// DeliveryID returns the key used to detect duplicate deliveries. If nil,
+	// the SHA-256 of the event type and payload without the per-delivery
+	// timestamp and sign is used, so a redelivery is only handled once.
+	DeliveryID func(r *http.Request, payload []byte) string
+
+	// ErrorLog logs the errors returned by callbacks, which are not sent back
+	// to the sender. If nil, the log package's standard logger is used.
+	ErrorLog *log.Logger
+
+	mu          sync.RWMutex
+	callbacks   map[string][]func(ctx context.Context, event interface{}) error
+	middlewares []func(http.Handler) http.Handler
+
+	seenMu sync.Mutex
+	seen   map[string]*list.Element
+	order  *list.List // 按时间排序的 delivery ID, 最早的在最前面
+}
+
+// seenDelivery is an entry of the de-duplication list.
+type seenDelivery struct {
+	id   string
+	at   time.Time
+	done bool // false 表示还在处理
+}
+
+// NewHookHandler returns a HookHandler authenticating deliveries with secret,
+// limiting payloads to 25MB and dropping duplicates seen in the last hour.
+func NewHookHandler(secret []byte) *HookHandler {
+	return &HookHandler{
+		Secret:       secret,
+		MaxBodyBytes: 25 << 20,
+		DedupWindow:  time.Hour,
+	}
+}
+
+// On registers a callback for the given webhook event type. The event passed
+// to fn is the value returned by ParseWebHook for that type. Several callbacks
+// may be registered for one event type; they run in registration order and
+// the first error stops the chain.
+func (h *HookHandler) On(eventType string, fn func(ctx context.Context, event interface{}) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.callbacks == nil {
+		h.callbacks = make(map[string][]func(ctx context.Context, event interface{}) error)
+	}
+	h.callbacks[eventType] = append(h.callbacks[eventType], fn)
+}
+
+// OnPush registers a callback for Push Hook deliveries.
+func (h *HookHandler) OnPush(fn func(ctx context.Context, event *PushHookEvent) error) {
+	h.On(PushHookEventType, func(ctx context.Context, event interface{}) error {
+		return fn(ctx, event.(*PushHookEvent))
+	})
+}
+
+// OnTagPush registers a callback for Tag Push Hook deliveries.
+func (h *HookHandler) OnTagPush(fn func(ctx context.Context, event *TagPushHookEvent) error) {
+	h.On(TagPushHookEventType, func(ctx context.Context, event interface{}) error {
+		return fn(ctx, event.(*TagPushHookEvent))
+	})
+}
+
+// OnIssue registers a callback for Issue Hook deliveries.
+func (h *HookHandler) OnIssue(fn func(ctx context.Context, event *IssueHookEvent) error) {
+	h.On(IssueHookEventType, func(ctx context.Context, event interface{}) error {
+		return fn(ctx, event.(*IssueHookEvent))
+	})
+}
+
+// OnNote registers a callback for Note Hook deliveries.
+func (h *HookHandler) OnNote(fn func(ctx context.Context, event *NoteHookEvent) error) {
+	h.On(NoteHookEventType, func(ctx context.Context, event interface{}) error {
+		return fn(ctx, event.(*NoteHookEvent))
+	})
+}
+
+// OnMergeRequest registers a callback for Merge Request Hook deliveries.
+func (h *HookHandler) OnMergeRequest(fn func(ctx context.Context, event *MergeRequestHookEvent) error) {
+	h.On(MergeRequestHookEventType, func(ctx context.Context, event interface{}) error {
+		return fn(ctx, event.(*MergeRequestHookEvent))
+	})
+}
+
+// Use appends middleware wrapping every delivery. The first middleware
+// registered is the outermost one.
+func (h *HookHandler) Use(middleware ...func(http.Handler) http.Handler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.middlewares = append(h.middlewares, middleware...)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *HookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	var next http.Handler = http.HandlerFunc(h.serveHook)
+	for i := len(h.middlewares) - 1; i >= 0; i-- {
+		next = h.middlewares[i](next)
+	}
+	h.mu.RUnlock()
+
+	next.ServeHTTP(w, r)
+}
+
+func (h *HookHandler) serveHook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	eventType := WebHookType(r)
+	if newHookEvent(eventType) == nil {
+		http.Error(w, fmt.Sprintf("unknown %v: %q", EventTypeHeader, eventType), http.StatusBadRequest)
+		return
+	}
+
+	if len(h.Secret) > 0 {
+		if err := ValidateToken(r.Header.Get(TokenHeader), r.Header.Get(TimestampHeader), h.Secret); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	body := io.Reader(r.Body)
+	if h.MaxBodyBytes > 0 {
+		body = io.LimitReader(r.Body, h.MaxBodyBytes+1)
+	}
+	payload, err := ioutil.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.MaxBodyBytes > 0 && int64(len(payload)) > h.MaxBodyBytes {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	event, err := ParseWebHook(eventType, payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.RLock()
+	callbacks := h.callbacks[eventType]
+	h.mu.RUnlock()
+	if len(callbacks) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	succeeded := false
+	if h.DedupWindow > 0 {
+		id := h.deliveryID(r, payload)
+		switch h.markSeen(id) {
+		case deliveryDone:
+			w.WriteHeader(http.StatusOK)
+			return
+		case deliveryRunning:
+			// 第一次推送还没处理完, 不能确认, 可能还会失败
+			http.Error(w, "delivery in progress", http.StatusConflict)
+			return
+		}
+		defer func() {
+			// 处理失败或者回调 panic 的 delivery 不算处理过, gitee 重新推送时还要再处理
+			if succeeded {
+				h.markDone(id)
+			} else {
+				h.forget(id)
+			}
+		}()
+	}
+
+	for _, fn := range callbacks {
+		if err := fn(r.Context(), event); err != nil {
+			// 回调的错误可能带着内部信息, 只写日志, 不返回给发送方
+			h.logf("gitee: %v callback: %v", eventType, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+	}
+	succeeded = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *HookHandler) logf(format string, args ...interface{}) {
+	if h.ErrorLog != nil {
+		h.ErrorLog.Printf(format, args...)
+	} else {
+		log.Printf(format, args...)
+	}
+}
+
+func (h *HookHandler) deliveryID(r *http.Request, payload []byte) string {
+	if h.DeliveryID != nil {
+		return h.DeliveryID(r, payload)
+	}
+	sum := sha256.New()
+	sum.Write([]byte(WebHookType(r) + "\n"))
+	sum.Write(stablePayload(payload))
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// stablePayload returns payload without the fields gitee sets anew for every
+// delivery, timestamp and sign. The keys of the result are sorted.
+func stablePayload(payload []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return payload
+	}
+	delete(fields, "timestamp")
+	delete(fields, "sign")
+	stable, err := json.Marshal(fields)
+	if err != nil {
+		return payload
+	}
+	return stable
+}
+
+// Delivery states returned by markSeen.
+const (
+	deliveryNew     = iota // 第一次收到
+	deliveryRunning        // 同样的 delivery 正在处理
+	deliveryDone           // 同样的 delivery 已经处理成功
+)
+
+// markSeen records id as running and returns its state within DedupWindow
+// before the call.
+func (h *HookHandler) markSeen(id string) int {
+	h.seenMu.Lock()
+	defer h.seenMu.Unlock()
+
+	if h.seen == nil {
+		h.seen = make(map[string]*list.Element)
+		h.order = list.New()
+	}
+
+	now := time.Now()
+	for e := h.order.Front(); e != nil; e = h.order.Front() {
+		d := e.Value.(*seenDelivery)
+		if now.Sub(d.at) < h.DedupWindow {
+			break
+		}
+		h.order.Remove(e)
+		delete(h.seen, d.id)
+	}
+
+	if e, ok := h.seen[id]; ok {
+		if e.Value.(*seenDelivery).done {
+			return deliveryDone
+		}
+		return deliveryRunning
+	}
+	h.seen[id] = h.order.PushBack(&seenDelivery{id: id, at: now})
+	return deliveryNew
+}
+
+// markDone records that the delivery id was handled.
+func (h *HookHandler) markDone(id string) {
+	h.seenMu.Lock()
+	defer h.seenMu.Unlock()
+	if e, ok := h.seen[id]; ok {
+		e.Value.(*seenDelivery).done = true
+	}
+}
+
+func (h *HookHandler) forget(id string) {
+	h.seenMu.Lock()
+	defer h.seenMu.Unlock()
+	if e, ok := h.seen[id]; ok {
+		h.order.Remove(e)
+		delete(h.seen, id)
+	}
+}
@@ -0,0 +1,220 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hookNames maps webhook event types to the hook_name gitee puts in payloads.
+var hookNames = map[string]string{
+	PushHookEventType:         "push_hooks",
+	TagPushHookEventType:      "tag_push_hooks",
+	IssueHookEventType:        "issue_hooks",
+	NoteHookEventType:         "note_hooks",
+	MergeRequestHookEventType: "merge_request_hooks",
+}
+
+// HookDelivery describes a webhook delivery to simulate, e.g. when testing a
+// hook receiver locally without pushing to a real repository.
+type HookDelivery struct {
+	EventType string // X-Gitee-Event, 如 PushHookEventType
+	Payload   []byte // JSON payload, 可以是从 gitee 上复制下来的真实数据
+
+	// Secret is the hook password or sign key, EncryptionType says which one
+	// (HookEncryptionPassword or HookEncryptionSign).
+	Secret         []byte
+	EncryptionType int
+
+	// Timestamp of the delivery. Defaults to the current time.
+	Timestamp time.Time
+}
+
+// NewHookRequest builds the request gitee would send to url for delivery d:
+// the same headers, and hook_name, timestamp, password and sign in the
+// payload filled in to match them. The rest of the payload is sent byte for
+// byte as given.
+func NewHookRequest(url string, d *HookDelivery) (*http.Request, error) {
+	ts := d.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	timestamp := strconv.FormatInt(ts.UnixNano()/int64(time.Millisecond), 10)
+
+	var token string
+	if len(d.Secret) > 0 {
+		if d.EncryptionType == HookEncryptionSign {
+			token = genSignature(timestamp, d.Secret)
+		} else {
+			token = string(d.Secret)
+		}
+	}
+
+	set := []jsonField{{"timestamp", timestamp}}
+	if name, ok := hookNames[d.EventType]; ok {
+		set = append([]jsonField{{"hook_name", name}}, set...)
+	}
+	del := "sign"
+	if d.EncryptionType == HookEncryptionSign {
+		set, del = append(set, jsonField{"sign", token}), "password"
+	} else {
+		set = append(set, jsonField{"password", token})
+	}
+	payload, err := spliceJSON(d.Payload, set, del)
+	if err != nil { // 不是 JSON 对象的 payload 原样发送
+		payload = d.Payload
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "git-oschina-hook")
+	req.Header.Set(EventTypeHeader, d.EventType)
+	req.Header.Set("X-Git-Oschina-Event", d.EventType)
+	req.Header.Set("X-Gitee-Ping", "false")
+	req.Header.Set(TimestampHeader, timestamp)
+	if token != "" {
+		req.Header.Set(TokenHeader, token)
+	}
+	return req, nil
+}
+
+// SendHook sends delivery d to url with httpClient, or http.DefaultClient if
+// it is nil. The caller must close the response body.
+func SendHook(ctx context.Context, httpClient *http.Client, url string, d *HookDelivery) (*http.Response, error) {
+	if ctx == nil {
+		return nil, errNonNilContext
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	req, err := NewHookRequest(url, d)
+	if err != nil {
+		return nil, err
+	}
+	return httpClient.Do(req.WithContext(ctx))
+}
+
+// SetPayloadField returns the webhook payload with its top level string field
+// key set to value, leaving the other bytes of payload untouched. A field that
+// is not in payload is appended.
+func SetPayloadField(payload []byte, key, value string) ([]byte, error) {
+	return spliceJSON(payload, []jsonField{{key, value}}, "")
+}
+
+// jsonField is a top-level string field set by spliceJSON.
+type jsonField struct {
+	key, value string
+}
+
+// spliceJSON sets the string fields set and removes the field del, if not
+// empty, of the JSON object payload, leaving the other bytes of payload untouched. Fields that
+// are not in payload are appended in order.
+func spliceJSON(payload []byte, set []jsonField, del string) ([]byte, error) {
+	// member 是一个字段在 payload 里面的位置, start 包括前面的逗号和空白
+	type member struct {
+		start, valueStart, end int
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("payload is not a JSON object")
+	}
+	var members []member
+	index := make(map[string]int)
+	prev := int(dec.InputOffset())
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		end := int(dec.InputOffset())
+		index[tok.(string)] = len(members)
+		members = append(members, member{start: prev, valueStart: end - len(raw), end: end})
+		prev = end
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	// 从后往前改, 前面的位置就不会变
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	remaining := len(members)
+	if i, ok := index[del]; ok && del != "" {
+		m := members[i]
+		switch {
+		case i > 0:
+			edits = append(edits, edit{m.start, m.end, ""})
+		case len(members) > 1:
+			// 第一个字段去掉到下一个字段的 key 为止, 保留前面的空白
+			next := members[1].start
+			next += bytes.IndexByte(payload[next:], ',') + 1
+			next += len(payload[next:]) - len(bytes.TrimLeft(payload[next:], " \t\r\n"))
+			start := m.start + len(payload[m.start:]) - len(bytes.TrimLeft(payload[m.start:], " \t\r\n"))
+			edits = append(edits, edit{start, next, ""})
+		default:
+			edits = append(edits, edit{m.start, m.end, ""})
+		}
+		remaining--
+	}
+	var appended strings.Builder
+	for _, f := range set {
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[f.key]; ok {
+			edits = append(edits, edit{members[i].valueStart, members[i].end, string(value)})
+			continue
+		}
+		if remaining > 0 {
+			appended.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		appended.Write(key)
+		appended.WriteByte(':')
+		appended.Write(value)
+		remaining++
+	}
+	if appended.Len() > 0 {
+		edits = append(edits, edit{prev, prev, appended.String()})
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), payload...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out, nil
+}
@@ -0,0 +1,330 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import "time"
+
+// 这里是 gitee WebHook 推送过来的数据格式, 和 API 返回的结构体 字段有些不一样, 所以单独定义.
+// https://gitee.com/help/articles/4271
+
+// HookMeta holds the fields gitee adds to every webhook delivery.
+type HookMeta struct {
+	HookName  *string    `json:"hook_name,omitempty"` // push_hooks, tag_push_hooks, issue_hooks, note_hooks, merge_request_hooks
+	HookID    *int64     `json:"hook_id,omitempty"`
+	HookURL   *string    `json:"hook_url,omitempty"`
+	Password  *string    `json:"password,omitempty"` // WebHook 密码, 加密类型为签名密钥时这里为空
+	Timestamp *Timestamp `json:"timestamp,omitempty"`
+	Sign      *string    `json:"sign,omitempty"` // 签名, 加密类型为密码时这里为空
+}
+
+// HookUser represents a user as sent in webhook payloads.
+type HookUser struct {
+	ID        *int64     `json:"id,omitempty"`
+	Login     *string    `json:"login,omitempty"`
+	Name      *string    `json:"name,omitempty"`
+	Username  *string    `json:"username,omitempty"`
+	UserName  *string    `json:"user_name,omitempty"`
+	Email     *string    `json:"email,omitempty"`
+	URL       *string    `json:"url,omitempty"`
+	AvatarURL *string    `json:"avatar_url,omitempty"`
+	HTMLURL   *string    `json:"html_url,omitempty"`
+	Type      *string    `json:"type,omitempty"`
+	SiteAdmin *bool      `json:"site_admin,omitempty"`
+	Remark    *string    `json:"remark,omitempty"`
+	Time      *time.Time `json:"time,omitempty"`
+}
+
+func (u HookUser) String() string {
+	return Stringify(u)
+}
+
+// HookRepository represents a repository as sent in webhook payloads
+// (both the "repository" and the "project" fields).
+type HookRepository struct {
+	ID                *int64     `json:"id,omitempty"`
+	Name              *string    `json:"name,omitempty"`
+	Path              *string    `json:"path,omitempty"`
+	FullName          *string    `json:"full_name,omitempty"`
+	Owner             *HookUser  `json:"owner,omitempty"`
+	Private           *bool      `json:"private,omitempty"`
+	Public            *bool      `json:"public,omitempty"`
+	Fork              *bool      `json:"fork,omitempty"`
+	Description       *string    `json:"description,omitempty"`
+	URL               *string    `json:"url,omitempty"`
+	HTMLURL           *string    `json:"html_url,omitempty"`
+	GitURL            *string    `json:"git_url,omitempty"`
+	SSHURL            *string    `json:"ssh_url,omitempty"`
+	CloneURL          *string    `json:"clone_url,omitempty"`
+	SVNURL            *string    `json:"svn_url,omitempty"`
+	GitHTTPURL        *string    `json:"git_http_url,omitempty"`
+	GitSSHURL         *string    `json:"git_ssh_url,omitempty"`
+	GitSVNURL         *string    `json:"git_svn_url,omitempty"`
+	Homepage          *string    `json:"homepage,omitempty"`
+	Language          *string    `json:"language,omitempty"`
+	License           *string    `json:"license,omitempty"`
+	DefaultBranch     *string    `json:"default_branch,omitempty"`
+	Namespace         *string    `json:"namespace,omitempty"`
+	NameWithNamespace *string    `json:"name_with_namespace,omitempty"`
+	PathWithNamespace *string    `json:"path_with_namespace,omitempty"`
+	StargazersCount   *int       `json:"stargazers_count,omitempty"`
+	WatchersCount     *int       `json:"watchers_count,omitempty"`
+	ForksCount        *int       `json:"forks_count,omitempty"`
+	OpenIssuesCount   *int       `json:"open_issues_count,omitempty"`
+	HasIssues         *bool      `json:"has_issues,omitempty"`
+	HasWiki           *bool      `json:"has_wiki,omitempty"`
+	HasPages          *bool      `json:"has_pages,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+	PushedAt          *time.Time `json:"pushed_at,omitempty"`
+}
+
+func (r HookRepository) String() string {
+	return Stringify(r)
+}
+
+// HookEnterprise is the enterprise a repository belongs to, if any.
+type HookEnterprise struct {
+	Name *string `json:"name,omitempty"`
+	URL  *string `json:"url,omitempty"`
+}
+
+// HookCommit represents a commit in a push webhook payload.
+type HookCommit struct {
+	ID        *string    `json:"id,omitempty"`
+	TreeID    *string    `json:"tree_id,omitempty"`
+	ParentIDs []string   `json:"parent_ids,omitempty"`
+	Distinct  *bool      `json:"distinct,omitempty"`
+	Message   *string    `json:"message,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	URL       *string    `json:"url,omitempty"`
+	Author    *HookUser  `json:"author,omitempty"`
+	Committer *HookUser  `json:"committer,omitempty"`
+	Added     []string   `json:"added,omitempty"`
+	Removed   []string   `json:"removed,omitempty"`
+	Modified  []string   `json:"modified,omitempty"`
+}
+
+func (c HookCommit) String() string {
+	return Stringify(c)
+}
+
+// HookIssue represents an issue in webhook payloads.
+type HookIssue struct {
+	ID            *int64      `json:"id,omitempty"`
+	HTMLURL       *string     `json:"html_url,omitempty"`
+	Number        *string     `json:"number,omitempty"`
+	Title         *string     `json:"title,omitempty"`
+	Body          *string     `json:"body,omitempty"`
+	User          *HookUser   `json:"user,omitempty"`
+	Labels        []*Label    `json:"labels,omitempty"`
+	State         *string     `json:"state,omitempty"`
+	StateName     *string     `json:"state_name,omitempty"` // 状态的中文名, 如: 待办的
+	TypeName      *string     `json:"type_name,omitempty"`  // 任务类型名, 如: 任务
+	Assignee      *HookUser   `json:"assignee,omitempty"`
+	Collaborators []*HookUser `json:"collaborators,omitempty"`
+	Milestone     *Milestone  `json:"milestone,omitempty"`
+	Comments      *int        `json:"comments,omitempty"`
+	CreatedAt     *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time  `json:"updated_at,omitempty"`
+}
+
+func (i HookIssue) String() string {
+	return Stringify(i)
+}
+
+// HookNote represents a comment in webhook payloads.
+type HookNote struct {
+	ID        *int64     `json:"id,omitempty"`
+	Body      *string    `json:"body,omitempty"`
+	User      *HookUser  `json:"user,omitempty"`
+	HTMLURL   *string    `json:"html_url,omitempty"`
+	Position  *string    `json:"position,omitempty"`
+	CommitID  *string    `json:"commit_id,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+func (n HookNote) String() string {
+	return Stringify(n)
+}
+
+// HookBranch represents the head or base branch of a pull request in
+// webhook payloads.
+type HookBranch struct {
+	Label *string         `json:"label,omitempty"`
+	Ref   *string         `json:"ref,omitempty"`
+	SHA   *string         `json:"sha,omitempty"`
+	User  *HookUser       `json:"user,omitempty"`
+	Repo  *HookRepository `json:"repo,omitempty"`
+}
+
+// HookPullRequest represents a pull request in webhook payloads.
+type HookPullRequest struct {
+	ID                 *int64      `json:"id,omitempty"`
+	Number             *int        `json:"number,omitempty"`
+	State              *string     `json:"state,omitempty"`
+	Title              *string     `json:"title,omitempty"`
+	Body               *string     `json:"body,omitempty"`
+	HTMLURL            *string     `json:"html_url,omitempty"`
+	DiffURL            *string     `json:"diff_url,omitempty"`
+	PatchURL           *string     `json:"patch_url,omitempty"`
+	User               *HookUser   `json:"user,omitempty"`
+	Assignee           *HookUser   `json:"assignee,omitempty"`
+	Assignees          []*HookUser `json:"assignees,omitempty"`
+	Tester             *HookUser   `json:"tester,omitempty"`
+	Testers            []*HookUser `json:"testers,omitempty"`
+	NeedReview         *bool       `json:"need_review,omitempty"`
+	NeedTest           *bool       `json:"need_test,omitempty"`
+	Labels             []*Label    `json:"labels,omitempty"`
+	Milestone          *Milestone  `json:"milestone,omitempty"`
+	Head               *HookBranch `json:"head,omitempty"`
+	Base               *HookBranch `json:"base,omitempty"`
+	Merged             *bool       `json:"merged,omitempty"`
+	Mergeable          *bool       `json:"mergeable,omitempty"`
+	MergeStatus        *string     `json:"merge_status,omitempty"`
+	MergeCommitSHA     *string     `json:"merge_commit_sha,omitempty"`
+	MergeReferenceName *string     `json:"merge_reference_name,omitempty"`
+	UpdatedBy          *HookUser   `json:"updated_by,omitempty"`
+	Comments           *int        `json:"comments,omitempty"`
+	Commits            *int        `json:"commits,omitempty"`
+	Additions          *int        `json:"additions,omitempty"`
+	Deletions          *int        `json:"deletions,omitempty"`
+	ChangedFiles       *int        `json:"changed_files,omitempty"`
+	CreatedAt          *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt          *time.Time  `json:"updated_at,omitempty"`
+	ClosedAt           *time.Time  `json:"closed_at,omitempty"`
+	MergedAt           *time.Time  `json:"merged_at,omitempty"`
+}
+
+func (p HookPullRequest) String() string {
+	return Stringify(p)
+}
+
+// PushHookEvent is sent when commits are pushed to a branch (Push Hook).
+type PushHookEvent struct {
+	HookMeta
+	Ref                *string         `json:"ref,omitempty"`
+	Before             *string         `json:"before,omitempty"`
+	After              *string         `json:"after,omitempty"`
+	TotalCommitsCount  *int            `json:"total_commits_count,omitempty"`
+	CommitsMoreThanTen *bool           `json:"commits_more_than_ten,omitempty"` // commits 里面最多只有 10 个
+	Created            *bool           `json:"created,omitempty"`
+	Deleted            *bool           `json:"deleted,omitempty"`
+	Compare            *string         `json:"compare,omitempty"`
+	Commits            []*HookCommit   `json:"commits,omitempty"`
+	HeadCommit         *HookCommit     `json:"head_commit,omitempty"`
+	Repository         *HookRepository `json:"repository,omitempty"`
+	Project            *HookRepository `json:"project,omitempty"`
+	UserID             *int64          `json:"user_id,omitempty"`
+	UserName           *string         `json:"user_name,omitempty"`
+	User               *HookUser       `json:"user,omitempty"`
+	Pusher             *HookUser       `json:"pusher,omitempty"`
+	Sender             *HookUser       `json:"sender,omitempty"`
+	Enterprise         *HookEnterprise `json:"enterprise,omitempty"`
+}
+
+func (e PushHookEvent) String() string {
+	return Stringify(e)
+}
+
+// TagPushHookEvent is sent when a tag is pushed or deleted (Tag Push Hook).
+// 数据格式和 PushHookEvent 一样, 只是 Ref 是 refs/tags/xxx
+type TagPushHookEvent PushHookEvent
+
+func (e TagPushHookEvent) String() string {
+	return Stringify(e)
+}
+
+// IssueHookEvent is sent when an issue is created, updated, or its state
+// changes (Issue Hook).
+type IssueHookEvent struct {
+	HookMeta
+	Action      *string         `json:"action,omitempty"` // open, delete, state_change, assign ...
+	Issue       *HookIssue      `json:"issue,omitempty"`
+	Repository  *HookRepository `json:"repository,omitempty"`
+	Project     *HookRepository `json:"project,omitempty"`
+	Sender      *HookUser       `json:"sender,omitempty"`
+	TargetUser  *HookUser       `json:"target_user,omitempty"`
+	User        *HookUser       `json:"user,omitempty"`
+	Assignee    *HookUser       `json:"assignee,omitempty"`
+	UpdatedBy   *HookUser       `json:"updated_by,omitempty"`
+	IID         *string         `json:"iid,omitempty"`
+	Title       *string         `json:"title,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	State       *string         `json:"state,omitempty"`
+	Milestone   *string         `json:"milestone,omitempty"`
+	URL         *string         `json:"url,omitempty"`
+	Enterprise  *HookEnterprise `json:"enterprise,omitempty"`
+}
+
+func (e IssueHookEvent) String() string {
+	return Stringify(e)
+}
+
+// NoteHookEvent is sent when a comment is made on an issue, pull request or
+// commit (Note Hook).
+type NoteHookEvent struct {
+	HookMeta
+	Action        *string          `json:"action,omitempty"` // comment, edited
+	Comment       *HookNote        `json:"comment,omitempty"`
+	Repository    *HookRepository  `json:"repository,omitempty"`
+	Project       *HookRepository  `json:"project,omitempty"`
+	Author        *HookUser        `json:"author,omitempty"`
+	Sender        *HookUser        `json:"sender,omitempty"`
+	URL           *string          `json:"url,omitempty"`
+	Note          *string          `json:"note,omitempty"`
+	NoteableType  *string          `json:"noteable_type,omitempty"` // 被评论的目标类型: Issue, PullRequest, Commit
+	NoteableID    *int64           `json:"noteable_id,omitempty"`
+	Title         *string          `json:"title,omitempty"`
+	PerIID        *string          `json:"per_iid,omitempty"`
+	ShortCommitID *string          `json:"short_commit_id,omitempty"`
+	Issue         *HookIssue       `json:"issue,omitempty"`        // 评论 Issue 时才有
+	PullRequest   *HookPullRequest `json:"pull_request,omitempty"` // 评论 Pull Request 时才有
+	Enterprise    *HookEnterprise  `json:"enterprise,omitempty"`
+}
+
+func (e NoteHookEvent) String() string {
+	return Stringify(e)
+}
+
+// MergeRequestHookEvent is sent when a pull request is opened, updated,
+// closed or merged (Merge Request Hook).
+type MergeRequestHookEvent struct {
+	HookMeta
+	Action         *string          `json:"action,omitempty"` // open, update, close, merge, test, tested, assign, approved ...
+	ActionDesc     *string          `json:"action_desc,omitempty"`
+	PullRequest    *HookPullRequest `json:"pull_request,omitempty"`
+	Number         *int             `json:"number,omitempty"`
+	IID            *int             `json:"iid,omitempty"`
+	Title          *string          `json:"title,omitempty"`
+	Body           *string          `json:"body,omitempty"`
+	State          *string          `json:"state,omitempty"`
+	MergeStatus    *string          `json:"merge_status,omitempty"`
+	MergeCommitSHA *string          `json:"merge_commit_sha,omitempty"`
+	URL            *string          `json:"url,omitempty"`
+	SourceBranch   *string          `json:"source_branch,omitempty"`
+	TargetBranch   *string          `json:"target_branch,omitempty"`
+	Repository     *HookRepository  `json:"repository,omitempty"`
+	Project        *HookRepository  `json:"project,omitempty"`
+	Author         *HookUser        `json:"author,omitempty"`
+	UpdatedBy      *HookUser        `json:"updated_by,omitempty"`
+	Sender         *HookUser        `json:"sender,omitempty"`
+	TargetUser     *HookUser        `json:"target_user,omitempty"`
+	Enterprise     *HookEnterprise  `json:"enterprise,omitempty"`
+}
+
+func (e MergeRequestHookEvent) String() string {
+	return Stringify(e)
+}
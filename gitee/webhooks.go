@@ -0,0 +1,209 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package gitee
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// EventTypeHeader is the gitee header key used to pass the event type.
+	EventTypeHeader = "X-Gitee-Event"
+	// TokenHeader carries the hook password, or the signature when the hook
+	// uses a sign key. 密码或者签名
+	TokenHeader = "X-Gitee-Token"
+	// TimestampHeader carries the millisecond timestamp the signature is computed over.
+	TimestampHeader = "X-Gitee-Timestamp"
+)
+
+// HookTimestampTolerance is how far X-Gitee-Timestamp may be from the local
+// clock before a signed delivery is rejected as stale. gitee 建议超过 1 小时的请求视为无效
+var HookTimestampTolerance = time.Hour
+
+var (
+	// ErrInvalidHookToken is returned when X-Gitee-Token matches neither the
+	// hook password nor the signature computed from the sign key.
+	ErrInvalidHookToken = errors.New("gitee: invalid webhook token or signature")
+	// ErrStaleHookTimestamp is returned when a signed delivery carries a missing,
+	// malformed or out of tolerance X-Gitee-Timestamp.
+	ErrStaleHookTimestamp = errors.New("gitee: webhook timestamp is missing or stale")
+)
+
+// gitee WebHook 的事件类型，即请求头 X-Gitee-Event 的值
+const (
+	PushHookEventType         = "Push Hook"
+	TagPushHookEventType      = "Tag Push Hook"
+	IssueHookEventType        = "Issue Hook"
+	NoteHookEventType         = "Note Hook"
+	MergeRequestHookEventType = "Merge Request Hook"
+)
+
+// newHookEvent returns a new, empty payload struct for the given webhook event type.
+func newHookEvent(eventType string) interface{} {
+	switch eventType {
+	case PushHookEventType:
+		return &PushHookEvent{}
+	case TagPushHookEventType:
+		return &TagPushHookEvent{}
+	case IssueHookEventType:
+		return &IssueHookEvent{}
+	case NoteHookEventType:
+		return &NoteHookEvent{}
+	case MergeRequestHookEventType:
+		return &MergeRequestHookEvent{}
+	}
+	return nil
+}
+
+// WebHookType returns the event type of webhook request r.
+func WebHookType(r *http.Request) string {
+	return r.Header.Get(EventTypeHeader)
+}
+
+// ParseWebHook parses the event payload. For recognized event types, a
+// value of the corresponding struct type will be returned. An error will be
+// returned for unrecognized event types.
+//
+// Example usage:
+//
+//  func (s *HookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//    payload, err := ioutil.ReadAll(r.Body)
+//    if err != nil { ... }
+//    event, err := gitee.ParseWebHook(gitee.WebHookType(r), payload)
+//    if err != nil { ... }
+//    switch event := event.(type) {
+//    case *gitee.PushHookEvent:
+//        processPushHookEvent(event)
+//    case *gitee.MergeRequestHookEvent:
+//        processMergeRequestHookEvent(event)
+//    ...
+//    }
+//  }
+func ParseWebHook(eventType string, payload []byte) (interface{}, error) {
+	event := newHookEvent(eventType)
+	if event == nil {
+		return nil, fmt.Errorf("unknown X-Gitee-Event in message: %v", eventType)
+	}
+
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// HasEvent reports whether the hook is configured to receive deliveries of
+// the given webhook event type. 对应 Hook 上面的 xxx_events 开关
+func (h *Hook) HasEvent(eventType string) bool {
+	var enabled *bool
+	switch eventType {
+	case PushHookEventType:
+		enabled = h.PushEvents
+	case TagPushHookEventType:
+		enabled = h.TagPushEvents
+	case IssueHookEventType:
+		enabled = h.IssuesEvents
+	case NoteHookEventType:
+		enabled = h.NoteEvents
+	case MergeRequestHookEventType:
+		enabled = h.MergeRequestsEvents
+	}
+	return enabled != nil && *enabled
+}
+
+// genSignature computes the signature gitee sends for hooks configured with a
+// sign key: base64(HMAC-SHA256(secret, timestamp + "\n" + secret)).
+func genSignature(timestamp string, secretToken []byte) string {
+	mac := hmac.New(sha256.New, secretToken)
+	mac.Write([]byte(timestamp + "\n"))
+	mac.Write(secretToken)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateToken checks the X-Gitee-Token and X-Gitee-Timestamp header values
+// against secretToken. Both hook encryption types are supported: with
+// HookEncryptionPassword the token is the password itself, with
+// HookEncryptionSign it is the signature of timestamp and secretToken, and the
+// timestamp must lie within HookTimestampTolerance of the local clock.
+// All comparisons are done in constant time.
+func ValidateToken(token, timestamp string, secretToken []byte) error {
+	if token == "" {
+		return ErrInvalidHookToken
+	}
+	// 加密类型为密码: X-Gitee-Token 就是密码明文
+	if subtle.ConstantTimeCompare([]byte(token), secretToken) == 1 {
+		return nil
+	}
+
+	// 加密类型为签名密钥: X-Gitee-Token 是签名, 有的版本会做一次 url encode
+	ms, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrStaleHookTimestamp
+	}
+	skew := time.Since(time.Unix(0, ms*int64(time.Millisecond)))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > HookTimestampTolerance {
+		return ErrStaleHookTimestamp
+	}
+
+	expected := []byte(genSignature(timestamp, secretToken))
+	if subtle.ConstantTimeCompare([]byte(token), expected) == 1 {
+		return nil
+	}
+	if unescaped, err := url.QueryUnescape(token); err == nil &&
+		subtle.ConstantTimeCompare([]byte(unescaped), expected) == 1 {
+		return nil
+	}
+	return ErrInvalidHookToken
+}
+
+// ValidatePayload validates an incoming gitee webhook request and returns the
+// (JSON) payload. secretToken is the password or sign key configured on the
+// hook; if it is empty the token check is skipped. See ValidateToken for the
+// supported encryption types.
+//
+// Example usage:
+//
+//  func (s *HookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//    payload, err := gitee.ValidatePayload(r, s.webhookSecretKey)
+//    if err != nil { ... }
+//    // Process payload...
+//  }
+func ValidatePayload(r *http.Request, secretToken []byte) (payload []byte, err error) {
+	if len(secretToken) > 0 {
+		err = ValidateToken(r.Header.Get(TokenHeader), r.Header.Get(TimestampHeader), secretToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	payload, err = ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
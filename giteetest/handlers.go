@@ -0,0 +1,924 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package giteetest
+
+import (
+	"encoding/base64"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+)
+
+// route is an endpoint of the fake server.
+type route struct {
+	method  string
+	pattern string
+	handle  func(s *Server, w http.ResponseWriter, r *http.Request, p params)
+}
+
+// routes are matched in order, so the fixed segments like
+// "repos/:owner/issues" come before "repos/:owner/:repo".
+var routes = []route{
+	{"GET", "user", (*Server).getAuthenticatedUser},
+	{"GET", "user/repos", (*Server).listAuthenticatedRepos},
+	{"POST", "user/repos", (*Server).createUserRepo},
+	{"GET", "users/:user", (*Server).getUser},
+	{"GET", "users/:user/repos", (*Server).listUserRepos},
+	{"GET", "orgs/:org/repos", (*Server).listOrgRepos},
+	{"POST", "orgs/:org/repos", (*Server).createOrgRepo},
+
+	{"POST", "repos/:owner/issues", (*Server).createIssue},
+	{"PATCH", "repos/:owner/issues/:number", (*Server).editIssue},
+
+	{"GET", "repos/:owner/:repo", (*Server).getRepo},
+	{"PATCH", "repos/:owner/:repo", (*Server).editRepo},
+	{"DELETE", "repos/:owner/:repo", (*Server).deleteRepo},
+
+	{"GET", "repos/:owner/:repo/branches", (*Server).listBranches},
+	{"POST", "repos/:owner/:repo/branches", (*Server).createBranch},
+	{"GET", "repos/:owner/:repo/branches/:branch", (*Server).getBranch},
+
+	{"GET", "repos/:owner/:repo/contents/*path", (*Server).getContents},
+	{"POST", "repos/:owner/:repo/contents/*path", (*Server).createFile},
+	{"PUT", "repos/:owner/:repo/contents/*path", (*Server).updateFile},
+	{"DELETE", "repos/:owner/:repo/contents/*path", (*Server).deleteFile},
+
+	{"GET", "repos/:owner/:repo/issues", (*Server).listIssues},
+	{"GET", "repos/:owner/:repo/issues/:number", (*Server).getIssue},
+
+	{"GET", "repos/:owner/:repo/pulls", (*Server).listPulls},
+	{"POST", "repos/:owner/:repo/pulls", (*Server).createPull},
+	{"GET", "repos/:owner/:repo/pulls/:number", (*Server).getPull},
+	{"PATCH", "repos/:owner/:repo/pulls/:number", (*Server).editPull},
+	{"GET", "repos/:owner/:repo/pulls/:number/merge", (*Server).isPullMerged},
+	{"PUT", "repos/:owner/:repo/pulls/:number/merge", (*Server).mergePull},
+
+	{"GET", "repos/:owner/:repo/hooks", (*Server).listHooks},
+	{"POST", "repos/:owner/:repo/hooks", (*Server).createHook},
+	{"GET", "repos/:owner/:repo/hooks/:id", (*Server).getHook},
+	{"PATCH", "repos/:owner/:repo/hooks/:id", (*Server).editHook},
+	{"DELETE", "repos/:owner/:repo/hooks/:id", (*Server).deleteHook},
+	{"POST", "repos/:owner/:repo/hooks/:id/tests", (*Server).testHook},
+}
+
+// users
+
+func (s *Server) getAuthenticatedUser(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, s.users[s.login])
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, p params) {
+	u, ok := s.users[p["user"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, u)
+}
+
+// repositories
+
+// reposOf returns the repositories of owner in creation order, without the
+// private ones unless withPrivate.
+func (s *Server) reposOf(owner string, withPrivate bool) []*gitee.Repository {
+	repos := []*gitee.Repository{}
+	for _, key := range s.order {
+		rs := s.repos[key]
+		if *rs.repo.Namespace.Path == owner && (withPrivate || !*rs.repo.Private) {
+			repos = append(repos, rs.repo)
+		}
+	}
+	return repos
+}
+
+func (s *Server) listAuthenticatedRepos(w http.ResponseWriter, r *http.Request, p params) {
+	writePage(w, r, s.reposOf(s.login, true))
+}
+
+func (s *Server) listUserRepos(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.users[p["user"]]; !ok {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	writePage(w, r, s.reposOf(p["user"], p["user"] == s.login))
+}
+
+func (s *Server) listOrgRepos(w http.ResponseWriter, r *http.Request, p params) {
+	if !s.orgs[p["org"]] {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	writePage(w, r, s.reposOf(p["org"], true))
+}
+
+func (s *Server) createUserRepo(w http.ResponseWriter, r *http.Request, p params) {
+	s.createRepo(w, r, s.login)
+}
+
+func (s *Server) createOrgRepo(w http.ResponseWriter, r *http.Request, p params) {
+	if !s.orgs[p["org"]] {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	s.createRepo(w, r, p["org"])
+}
+
+func (s *Server) createRepo(w http.ResponseWriter, r *http.Request, owner string) {
+	req := new(gitee.RepositoryCreateRequest)
+	if !readJSON(w, r, req) {
+		return
+	}
+	if req.Name == nil || *req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is missing")
+		return
+	}
+	repoPath := *req.Name
+	if req.Path != nil && *req.Path != "" {
+		repoPath = *req.Path
+	}
+	if _, ok := s.repos[owner+"/"+repoPath]; ok {
+		writeError(w, http.StatusBadRequest, "已存在同名的仓库")
+		return
+	}
+
+	rs := s.addRepo(owner, req)
+	if req.AutoInit != nil && *req.AutoInit {
+		s.commit(rs, "master", "README.md", []byte("# "+*req.Name+"\n"))
+	}
+	writeJSON(w, http.StatusCreated, rs.repo)
+}
+
+// repo returns the repository of the route, or writes 404.
+func (s *Server) repo(w http.ResponseWriter, p params) (*repoState, bool) {
+	rs, ok := s.repos[p["owner"]+"/"+p["repo"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found Project")
+	}
+	return rs, ok
+}
+
+func (s *Server) getRepo(w http.ResponseWriter, r *http.Request, p params) {
+	if rs, ok := s.repo(w, p); ok {
+		writeJSON(w, http.StatusOK, rs.repo)
+	}
+}
+
+func (s *Server) editRepo(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	// 解析成 map, 才能区分没传的字段和 false
+	var req map[string]interface{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if name, _ := req["name"].(string); name == "" {
+		writeError(w, http.StatusBadRequest, "name is missing")
+		return
+	}
+
+	repo := rs.repo
+	for key, value := range req {
+		switch v := value.(type) {
+		case string:
+			switch key {
+			case "name":
+				repo.Name = gitee.String(v)
+			case "description":
+				repo.Description = gitee.String(v)
+			case "homepage":
+				repo.Homepage = gitee.String(v)
+			case "default_branch":
+				if _, ok := rs.branches[v]; !ok {
+					writeError(w, http.StatusBadRequest, "分支不存在")
+					return
+				}
+				repo.DefaultBranch = gitee.String(v)
+			}
+		case bool:
+			switch key {
+			case "private":
+				repo.Private, repo.Public = gitee.Bool(v), gitee.Bool(!v)
+			case "has_issues":
+				repo.HasIssues = gitee.Bool(v)
+			case "has_wiki":
+				repo.HasWiki = gitee.Bool(v)
+			case "can_comment":
+				repo.CanComment = gitee.Bool(v)
+			case "pull_requests_enabled":
+				repo.PullRequestsEnabled = gitee.Bool(v)
+			}
+		}
+	}
+	repo.UpdatedAt = &gitee.Timestamp{Time: time.Now()}
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (s *Server) deleteRepo(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	key := *rs.repo.FullName
+	delete(s.repos, key)
+	for i, k := range s.order {
+		if k == key {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// branches
+
+func (s *Server) branch(rs *repoState, name string) *gitee.Branch {
+	return &gitee.Branch{
+		Name:      gitee.String(name),
+		Commit:    &gitee.BasicCommit{SHA: gitee.String(rs.branches[name]), URL: gitee.String(*rs.repo.URL + "/commits/" + rs.branches[name])},
+		Protected: gitee.Bool(false),
+	}
+}
+
+func (s *Server) listBranches(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	names := make([]string, 0, len(rs.branches))
+	for name := range rs.branches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// gitee 的分支列表不分页
+	branches := make([]*gitee.Branch, len(names))
+	for i, name := range names {
+		branches[i] = s.branch(rs, name)
+	}
+	writeJSON(w, http.StatusOK, branches)
+}
+
+func (s *Server) createBranch(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	req := new(gitee.BranchRequest)
+	if !readJSON(w, r, req) {
+		return
+	}
+	if req.BranchName == nil || *req.BranchName == "" {
+		writeError(w, http.StatusBadRequest, "branch_name is missing")
+		return
+	}
+	if _, ok := rs.branches[*req.BranchName]; ok {
+		writeError(w, http.StatusBadRequest, "分支名已存在")
+		return
+	}
+	from := "master"
+	if req.Refs != nil && *req.Refs != "" {
+		from = *req.Refs
+	}
+	if _, ok := rs.branches[from]; !ok {
+		writeError(w, http.StatusBadRequest, "分支不存在")
+		return
+	}
+
+	files := make(map[string][]byte, len(rs.files[from]))
+	for name, content := range rs.files[from] {
+		files[name] = content
+	}
+	rs.files[*req.BranchName] = files
+	rs.branches[*req.BranchName] = rs.branches[from]
+	writeJSON(w, http.StatusCreated, s.branch(rs, *req.BranchName))
+}
+
+func (s *Server) getBranch(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	if _, ok := rs.branches[p["branch"]]; !ok {
+		writeError(w, http.StatusNotFound, "Branch Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.branch(rs, p["branch"]))
+}
+
+// contents
+
+// ref returns the branch a contents call is about, or writes 404.
+func (s *Server) ref(w http.ResponseWriter, rs *repoState, ref string) (string, bool) {
+	if ref == "" {
+		ref = *rs.repo.DefaultBranch
+	}
+	if _, ok := rs.branches[ref]; !ok {
+		writeError(w, http.StatusNotFound, "Branch Not Found")
+		return "", false
+	}
+	return ref, true
+}
+
+func (s *Server) content(rs *repoState, branch, filePath string, content []byte) *gitee.RepositoryContent {
+	c := &gitee.RepositoryContent{
+		Type:    gitee.String("file"),
+		Name:    gitee.String(path.Base(filePath)),
+		Path:    gitee.String(filePath),
+		URL:     gitee.String(*rs.repo.URL + "/contents/" + filePath),
+		HTMLURL: gitee.String(*rs.repo.HTMLURL + "/blob/" + branch + "/" + filePath),
+	}
+	if content != nil {
+		c.Encoding = gitee.String("base64")
+		c.Size = gitee.Int(len(content))
+		c.Content = gitee.String(base64.StdEncoding.EncodeToString(content))
+		c.SHA = gitee.String(blobSHA(content))
+		c.DownloadURL = gitee.String(*rs.repo.HTMLURL + "/raw/" + branch + "/" + filePath)
+	}
+	return c
+}
+
+func (s *Server) getContents(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	branch, ok := s.ref(w, rs, r.URL.Query().Get("ref"))
+	if !ok {
+		return
+	}
+	files := rs.files[branch]
+	filePath := strings.Trim(p["path"], "/")
+
+	if content, ok := files[filePath]; ok {
+		writeJSON(w, http.StatusOK, s.content(rs, branch, filePath, content))
+		return
+	}
+
+	// 目录只列出直接的子文件和子目录
+	prefix := filePath + "/"
+	if filePath == "" {
+		prefix = ""
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for name := range files {
+		if strings.HasPrefix(name, prefix) {
+			child := strings.SplitN(strings.TrimPrefix(name, prefix), "/", 2)[0]
+			if !seen[child] {
+				seen[child] = true
+				names = append(names, child)
+			}
+		}
+	}
+	if len(names) == 0 {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	sort.Strings(names)
+
+	entries := make([]*gitee.RepositoryContent, len(names))
+	for i, name := range names {
+		if content, ok := files[prefix+name]; ok {
+			entries[i] = s.content(rs, branch, prefix+name, content)
+			entries[i].Content = nil
+			continue
+		}
+		entries[i] = s.content(rs, branch, prefix+name, nil)
+		entries[i].Type = gitee.String("dir")
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// fileRequest decodes the request of a file change and checks its branch.
+func (s *Server) fileRequest(w http.ResponseWriter, r *http.Request, p params) (*repoState, *gitee.RepositoryContentFileRequest, string, string, bool) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return nil, nil, "", "", false
+	}
+	req := new(gitee.RepositoryContentFileRequest)
+	if !readJSON(w, r, req) {
+		return nil, nil, "", "", false
+	}
+	if req.Message == nil || *req.Message == "" {
+		writeError(w, http.StatusBadRequest, "message is missing")
+		return nil, nil, "", "", false
+	}
+	ref := ""
+	if req.Branch != nil {
+		ref = *req.Branch
+	}
+	branch, ok := s.ref(w, rs, ref)
+	return rs, req, branch, strings.Trim(p["path"], "/"), ok
+}
+
+func (s *Server) writeFileCommit(w http.ResponseWriter, status int, rs *repoState, branch, filePath, message, sha string, content []byte) {
+	var c *gitee.RepositoryContent
+	if content != nil {
+		c = s.content(rs, branch, filePath, content)
+		c.Content = nil
+	}
+	// gitee.Commit 没有 sha 字段, 直接拼 JSON
+	writeJSON(w, status, map[string]interface{}{
+		"content": c,
+		"commit": map[string]interface{}{
+			"sha":     sha,
+			"message": message,
+			"parents": []*gitee.BasicCommit{},
+		},
+	})
+}
+
+func (s *Server) createFile(w http.ResponseWriter, r *http.Request, p params) {
+	rs, req, branch, filePath, ok := s.fileRequest(w, r, p)
+	if !ok {
+		return
+	}
+	if _, ok := rs.files[branch][filePath]; ok {
+		writeError(w, http.StatusBadRequest, "文件名已存在")
+		return
+	}
+	if req.Content == nil {
+		req.Content = []byte{}
+	}
+	sha := s.commit(rs, branch, filePath, req.Content)
+	s.writeFileCommit(w, http.StatusCreated, rs, branch, filePath, *req.Message, sha, req.Content)
+}
+
+func (s *Server) updateFile(w http.ResponseWriter, r *http.Request, p params) {
+	rs, req, branch, filePath, ok := s.fileRequest(w, r, p)
+	if !ok || !s.checkFileSHA(w, rs, branch, filePath, req) {
+		return
+	}
+	if req.Content == nil {
+		req.Content = []byte{}
+	}
+	sha := s.commit(rs, branch, filePath, req.Content)
+	s.writeFileCommit(w, http.StatusOK, rs, branch, filePath, *req.Message, sha, req.Content)
+}
+
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request, p params) {
+	rs, req, branch, filePath, ok := s.fileRequest(w, r, p)
+	if !ok || !s.checkFileSHA(w, rs, branch, filePath, req) {
+		return
+	}
+	sha := s.commit(rs, branch, filePath, nil)
+	s.writeFileCommit(w, http.StatusOK, rs, branch, filePath, *req.Message, sha, nil)
+}
+
+// checkFileSHA checks that the file exists and the request has its blob SHA.
+func (s *Server) checkFileSHA(w http.ResponseWriter, rs *repoState, branch, filePath string, req *gitee.RepositoryContentFileRequest) bool {
+	content, ok := rs.files[branch][filePath]
+	if !ok {
+		writeError(w, http.StatusNotFound, "文件不存在")
+		return false
+	}
+	if req.SHA == nil || *req.SHA == "" {
+		writeError(w, http.StatusBadRequest, "sha is missing")
+		return false
+	}
+	if *req.SHA != blobSHA(content) {
+		writeError(w, http.StatusBadRequest, "sha is invalid")
+		return false
+	}
+	return true
+}
+
+// issues
+
+// issueRequest is the body of creating and editing an issue.
+type issueRequest struct {
+	Repo  string  `json:"repo"`
+	Title *string `json:"title"`
+	Body  *string `json:"body"`
+	State *string `json:"state"`
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	state := r.URL.Query().Get("state")
+	if state == "" {
+		state = "open"
+	}
+	issues := []*gitee.Issue{}
+	for _, issue := range rs.issues {
+		if state == "all" || *issue.State == state {
+			issues = append(issues, issue)
+		}
+	}
+	writePage(w, r, issues)
+}
+
+func (s *Server) findIssue(rs *repoState, number string) *gitee.Issue {
+	for _, issue := range rs.issues {
+		if *issue.Number == number {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	issue := s.findIssue(rs, p["number"])
+	if issue == nil {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, issue)
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request, p params) {
+	req := new(issueRequest)
+	if !readJSON(w, r, req) {
+		return
+	}
+	if req.Title == nil || *req.Title == "" {
+		writeError(w, http.StatusBadRequest, "title is missing")
+		return
+	}
+	p["repo"] = req.Repo
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+
+	id := s.nextID()
+	number := "I" + strings.ToUpper(strconv.FormatInt(id+36*36*36, 36))
+	now := time.Now()
+	issue := &gitee.Issue{
+		ID:         gitee.Int64(id),
+		Number:     gitee.String(number),
+		State:      gitee.String("open"),
+		Title:      req.Title,
+		Body:       req.Body,
+		User:       s.users[s.login],
+		Repository: rs.repo,
+		HTMLURL:    gitee.String(*rs.repo.HTMLURL + "/issues/" + number),
+		CreatedAt:  &now,
+		UpdatedAt:  &now,
+		Comments:   gitee.Int(0),
+	}
+	rs.issues = append(rs.issues, issue)
+	writeJSON(w, http.StatusCreated, issue)
+}
+
+func (s *Server) editIssue(w http.ResponseWriter, r *http.Request, p params) {
+	req := new(issueRequest)
+	if !readJSON(w, r, req) {
+		return
+	}
+	p["repo"] = req.Repo
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	issue := s.findIssue(rs, p["number"])
+	if issue == nil {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+
+	if req.Title != nil {
+		issue.Title = req.Title
+	}
+	if req.Body != nil {
+		issue.Body = req.Body
+	}
+	if req.State != nil {
+		switch *req.State {
+		case "open", "progressing", "closed", "rejected":
+			issue.State = req.State
+		default:
+			writeError(w, http.StatusBadRequest, "state does not have a valid value")
+			return
+		}
+	}
+	now := time.Now()
+	issue.UpdatedAt = &now
+	writeJSON(w, http.StatusOK, issue)
+}
+
+// pull requests
+
+// pullRequest is the body of creating and editing a pull request.
+type pullRequest struct {
+	Title *string `json:"title"`
+	Head  string  `json:"head"`
+	Base  string  `json:"base"`
+	Body  *string `json:"body"`
+	State *string `json:"state"`
+}
+
+func (s *Server) listPulls(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	state := q.Get("state")
+	if state == "" {
+		state = "open"
+	}
+	head := q.Get("head")
+	if i := strings.Index(head, ":"); i >= 0 {
+		head = head[i+1:]
+	}
+
+	pulls := []*gitee.PullRequest{}
+	for _, pr := range rs.pulls {
+		if (state == "all" || *pr.State == state) &&
+			(head == "" || *pr.Head.Ref == head) &&
+			(q.Get("base") == "" || *pr.Base.Ref == q.Get("base")) {
+			pulls = append(pulls, pr)
+		}
+	}
+	writePage(w, r, pulls)
+}
+
+// pull returns the pull request of the route, or writes 404.
+func (s *Server) pull(w http.ResponseWriter, p params) (*repoState, *gitee.PullRequest, bool) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return nil, nil, false
+	}
+	number, _ := strconv.Atoi(p["number"])
+	for _, pr := range rs.pulls {
+		if *pr.Number == number {
+			return rs, pr, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "404 Not Found")
+	return nil, nil, false
+}
+
+func (s *Server) createPull(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	req := new(pullRequest)
+	if !readJSON(w, r, req) {
+		return
+	}
+	if req.Title == nil || *req.Title == "" {
+		writeError(w, http.StatusBadRequest, "title is missing")
+		return
+	}
+	head := req.Head
+	if i := strings.Index(head, ":"); i >= 0 {
+		head = head[i+1:]
+	}
+	if _, ok := rs.branches[head]; !ok {
+		writeError(w, http.StatusBadRequest, "源分支不存在")
+		return
+	}
+	if _, ok := rs.branches[req.Base]; !ok {
+		writeError(w, http.StatusBadRequest, "目标分支不存在")
+		return
+	}
+	for _, pr := range rs.pulls {
+		if *pr.State == "open" && *pr.Head.Ref == head && *pr.Base.Ref == req.Base {
+			writeError(w, http.StatusBadRequest, "已存在相同源分支、目标分支的 PR")
+			return
+		}
+	}
+
+	number := len(rs.pulls) + 1
+	now := time.Now()
+	pr := &gitee.PullRequest{
+		ID:        gitee.Int64(s.nextID()),
+		Number:    gitee.Int(number),
+		State:     gitee.String("open"),
+		Title:     req.Title,
+		Body:      req.Body,
+		User:      s.users[s.login],
+		HTMLURL:   gitee.String(*rs.repo.HTMLURL + "/pulls/" + strconv.Itoa(number)),
+		Head:      s.pullBranch(rs, head),
+		Base:      s.pullBranch(rs, req.Base),
+		Mergeable: gitee.Bool(true),
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+	rs.pulls = append(rs.pulls, pr)
+	writeJSON(w, http.StatusCreated, pr)
+}
+
+func (s *Server) pullBranch(rs *repoState, branch string) *gitee.PullRequestBranch {
+	return &gitee.PullRequestBranch{
+		Label: gitee.String(branch),
+		Ref:   gitee.String(branch),
+		SHA:   gitee.String(rs.branches[branch]),
+	}
+}
+
+func (s *Server) getPull(w http.ResponseWriter, r *http.Request, p params) {
+	if _, pr, ok := s.pull(w, p); ok {
+		writeJSON(w, http.StatusOK, pr)
+	}
+}
+
+func (s *Server) editPull(w http.ResponseWriter, r *http.Request, p params) {
+	_, pr, ok := s.pull(w, p)
+	if !ok {
+		return
+	}
+	req := new(pullRequest)
+	if !readJSON(w, r, req) {
+		return
+	}
+	if req.Title != nil {
+		pr.Title = req.Title
+	}
+	if req.Body != nil {
+		pr.Body = req.Body
+	}
+	if req.State != nil {
+		if *pr.State == "merged" || *req.State != "open" && *req.State != "closed" {
+			writeError(w, http.StatusBadRequest, "state does not have a valid value")
+			return
+		}
+		pr.State = req.State
+		now := time.Now()
+		pr.ClosedAt = nil
+		if *req.State == "closed" {
+			pr.ClosedAt = &now
+		}
+	}
+	now := time.Now()
+	pr.UpdatedAt = &now
+	writeJSON(w, http.StatusOK, pr)
+}
+
+func (s *Server) isPullMerged(w http.ResponseWriter, r *http.Request, p params) {
+	rs, pr, ok := s.pull(w, p)
+	if !ok {
+		return
+	}
+	if !rs.merged[*pr.Number] {
+		writeError(w, http.StatusNotFound, "Pull Request 未合并")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) mergePull(w http.ResponseWriter, r *http.Request, p params) {
+	rs, pr, ok := s.pull(w, p)
+	if !ok {
+		return
+	}
+	if *pr.State != "open" {
+		writeError(w, http.StatusBadRequest, "Pull Request 不是开启状态, 不能合并")
+		return
+	}
+
+	// 合并就是把源分支的文件都提交到目标分支
+	head, base := *pr.Head.Ref, *pr.Base.Ref
+	files := make(map[string][]byte, len(rs.files[head]))
+	for name, content := range rs.files[head] {
+		files[name] = content
+	}
+	rs.files[base] = files
+	sha := fakeSHA("merge " + rs.branches[base] + " " + rs.branches[head])
+	rs.branches[base] = sha
+
+	now := time.Now()
+	pr.State = gitee.String("merged")
+	pr.MergedAt = &now
+	pr.UpdatedAt = &now
+	rs.merged[*pr.Number] = true
+	writeJSON(w, http.StatusOK, map[string]interface{}{"sha": sha, "merged": true, "message": "Pull Request 已成功合并"})
+}
+
+// hooks
+
+// hook returns the hook of the route, or writes 404.
+func (s *Server) hook(w http.ResponseWriter, p params) (*repoState, int, bool) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return nil, 0, false
+	}
+	id, _ := strconv.ParseInt(p["id"], 10, 64)
+	for i, h := range rs.hooks {
+		if *h.ID == id {
+			return rs, i, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "404 Not Found")
+	return nil, 0, false
+}
+
+// applyHook sets the fields of h given in req.
+func applyHook(h *gitee.Hook, req *gitee.HookRequest) {
+	if req.URL != nil {
+		h.URL = req.URL
+	}
+	if req.Password != nil {
+		h.Password = req.Password
+	}
+	if req.PushEvents != nil {
+		h.PushEvents = req.PushEvents
+	}
+	if req.TagPushEvents != nil {
+		h.TagPushEvents = req.TagPushEvents
+	}
+	if req.IssuesEvents != nil {
+		h.IssuesEvents = req.IssuesEvents
+	}
+	if req.NoteEvents != nil {
+		h.NoteEvents = req.NoteEvents
+	}
+	if req.MergeRequestsEvents != nil {
+		h.MergeRequestsEvents = req.MergeRequestsEvents
+	}
+}
+
+func (s *Server) listHooks(w http.ResponseWriter, r *http.Request, p params) {
+	if rs, ok := s.repo(w, p); ok {
+		hooks := append([]*gitee.Hook{}, rs.hooks...)
+		writePage(w, r, hooks)
+	}
+}
+
+func (s *Server) createHook(w http.ResponseWriter, r *http.Request, p params) {
+	rs, ok := s.repo(w, p)
+	if !ok {
+		return
+	}
+	req := new(gitee.HookRequest)
+	if !readJSON(w, r, req) {
+		return
+	}
+	if req.URL == nil || *req.URL == "" {
+		writeError(w, http.StatusBadRequest, "url is missing")
+		return
+	}
+
+	h := &gitee.Hook{
+		ID:         gitee.Int64(s.nextID()),
+		ProjectID:  rs.repo.ID,
+		CreatedAt:  &gitee.Timestamp{Time: time.Now()},
+		PushEvents: gitee.Bool(true), // gitee 默认勾选 Push
+	}
+	applyHook(h, req)
+	rs.hooks = append(rs.hooks, h)
+	writeJSON(w, http.StatusCreated, h)
+}
+
+func (s *Server) getHook(w http.ResponseWriter, r *http.Request, p params) {
+	if rs, i, ok := s.hook(w, p); ok {
+		writeJSON(w, http.StatusOK, rs.hooks[i])
+	}
+}
+
+func (s *Server) editHook(w http.ResponseWriter, r *http.Request, p params) {
+	rs, i, ok := s.hook(w, p)
+	if !ok {
+		return
+	}
+	req := new(gitee.HookRequest)
+	if !readJSON(w, r, req) {
+		return
+	}
+	applyHook(rs.hooks[i], req)
+	writeJSON(w, http.StatusOK, rs.hooks[i])
+}
+
+func (s *Server) deleteHook(w http.ResponseWriter, r *http.Request, p params) {
+	rs, i, ok := s.hook(w, p)
+	if !ok {
+		return
+	}
+	rs.hooks = append(rs.hooks[:i], rs.hooks[i+1:]...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) testHook(w http.ResponseWriter, r *http.Request, p params) {
+	if _, _, ok := s.hook(w, p); ok {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,392 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Package giteetest provides an in-memory fake of the core gitee API v5
+// endpoints for tests of code built on the gitee package: users, repos,
+// branches, contents, issues, pull requests and hooks. State lives in memory,
+// list endpoints are paginated with gitee's Total_count, Total_page and Link
+// headers, and errors use gitee's {"message": ...} bodies.
+//
+//  srv := giteetest.NewServer()
+//  defer srv.Close()
+//  srv.AddRepo(giteetest.DefaultLogin, "hello")
+//  client := srv.Client()
+//  branches, _, err := client.Repositories.ListBranches(ctx, giteetest.DefaultLogin, "hello")
+package giteetest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+)
+
+// DefaultLogin is the login of the authenticated user of a new Server.
+const DefaultLogin = "tester"
+
+// Server is a fake gitee API server. Seed it with AddUser, AddOrg, AddRepo
+// and SetFile, and talk to it with the client returned by Client.
+type Server struct {
+	*httptest.Server
+
+	// Token, if set, must be sent by every request, in the Authorization
+	// header or as access_token parameter. Client sends it.
+	Token string
+
+	mu    sync.Mutex
+	login string
+	users map[string]*gitee.User
+	orgs  map[string]bool
+	repos map[string]*repoState // key 是 "owner/path"
+	order []string              // 仓库按创建顺序排列
+	id    int64
+}
+
+// repoState is the state of a repository.
+type repoState struct {
+	repo     *gitee.Repository
+	branches map[string]string            // branch -> head commit
+	files    map[string]map[string][]byte // branch -> path -> content
+	issues   []*gitee.Issue
+	pulls    []*gitee.PullRequest
+	merged   map[int]bool
+	hooks    []*gitee.Hook
+}
+
+// NewServer starts a fake server whose authenticated user is DefaultLogin.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		login: DefaultLogin,
+		users: make(map[string]*gitee.User),
+		orgs:  make(map[string]bool),
+		repos: make(map[string]*repoState),
+	}
+	s.Server = httptest.NewServer(s)
+	s.AddUser(DefaultLogin)
+	return s
+}
+
+// Client returns a gitee client talking to the server.
+func (s *Server) Client() *gitee.Client {
+	var hc *http.Client
+	if s.Token != "" {
+		hc = (&gitee.TokenTransport{Token: s.Token}).Client()
+	}
+	c := gitee.NewClient(hc)
+	c.BaseURL, _ = url.Parse(s.URL + "/api/v5/")
+	c.WebURL, _ = url.Parse(s.URL + "/")
+	return c
+}
+
+func (s *Server) nextID() int64 {
+	s.id++
+	return s.id
+}
+
+// AddUser adds a user, if it does not exist yet, and returns it.
+func (s *Server) AddUser(login string) *gitee.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addUser(login)
+}
+
+func (s *Server) addUser(login string) *gitee.User {
+	if u, ok := s.users[login]; ok {
+		return u
+	}
+	u := &gitee.User{BasicUser: &gitee.BasicUser{
+		ID:      gitee.Int64(s.nextID()),
+		Login:   gitee.String(login),
+		Name:    gitee.String(login),
+		HTMLURL: gitee.String(s.URL + "/" + login),
+		Type:    gitee.String("User"),
+	}}
+	s.users[login] = u
+	return u
+}
+
+// AddOrg adds an organization, repositories can then be created in it.
+func (s *Server) AddOrg(org string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orgs[org] = true
+}
+
+// AddRepo adds a public repository with a master branch holding a README.md
+// and returns it. owner is added as user unless it is an organization.
+func (s *Server) AddRepo(owner, name string) *gitee.Repository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs := s.addRepo(owner, &gitee.RepositoryCreateRequest{Name: gitee.String(name)})
+	s.commit(rs, "master", "README.md", []byte("# "+name+"\n"))
+	return rs.repo
+}
+
+func (s *Server) addRepo(owner string, req *gitee.RepositoryCreateRequest) *repoState {
+	if !s.orgs[owner] {
+		s.addUser(owner)
+	}
+	path := *req.Name
+	if req.Path != nil && *req.Path != "" {
+		path = *req.Path
+	}
+	fullName := owner + "/" + path
+	now := &gitee.Timestamp{Time: time.Now()}
+	private := req.Private != nil && *req.Private
+
+	repo := &gitee.Repository{
+		ID:            gitee.Int64(s.nextID()),
+		FullName:      gitee.String(fullName),
+		HumanName:     gitee.String(owner + "/" + *req.Name),
+		Name:          gitee.String(*req.Name),
+		Path:          gitee.String(path),
+		Description:   req.Description,
+		Homepage:      req.Homepage,
+		Private:       gitee.Bool(private),
+		Public:        gitee.Bool(!private),
+		Fork:          gitee.Bool(false),
+		HTMLURL:       gitee.String(s.URL + "/" + fullName),
+		URL:           gitee.String(s.URL + "/api/v5/repos/" + fullName),
+		DefaultBranch: gitee.String("master"),
+		HasIssues:     gitee.Bool(req.HasIssues == nil || *req.HasIssues),
+		HasWiki:       gitee.Bool(req.HasWiki == nil || *req.HasWiki),
+		Namespace:     &gitee.Namespace{Path: gitee.String(owner), Name: gitee.String(owner)},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if u, ok := s.users[owner]; ok {
+		repo.Owner = u
+	}
+
+	rs := &repoState{
+		repo:     repo,
+		branches: make(map[string]string),
+		files:    make(map[string]map[string][]byte),
+		merged:   make(map[int]bool),
+	}
+	s.repos[fullName] = rs
+	s.order = append(s.order, fullName)
+	return rs
+}
+
+// SetFile commits content to path on branch, creating the branch if needed.
+// Passing nil content deletes the file.
+func (s *Server) SetFile(owner, repo, branch, path string, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs, ok := s.repos[owner+"/"+repo]
+	if !ok {
+		return fmt.Errorf("giteetest: repository %s/%s does not exist", owner, repo)
+	}
+	s.commit(rs, branch, path, content)
+	return nil
+}
+
+// commit changes a file on branch and returns the new head commit.
+func (s *Server) commit(rs *repoState, branch, path string, content []byte) string {
+	files := rs.files[branch]
+	if files == nil {
+		files = make(map[string][]byte)
+		rs.files[branch] = files
+	}
+	if content == nil {
+		delete(files, path)
+	} else {
+		files[path] = content
+	}
+
+	sha := fakeSHA(fmt.Sprintf("%s %s %s %d", rs.branches[branch], branch, path, s.nextID()))
+	rs.branches[branch] = sha
+	rs.repo.PushedAt = &gitee.Timestamp{Time: time.Now()}
+	return sha
+}
+
+// fakeSHA returns a SHA-1 looking hash of s.
+func fakeSHA(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// blobSHA returns the SHA-1 git gives the blob content.
+func blobSHA(content []byte) string {
+	return fakeSHA(fmt.Sprintf("blob %d\x00%s", len(content), content))
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/api/v5/") {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	if s.Token != "" && !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "401 Unauthorized: Access token does not exist")
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v5/"), "/")
+	methodMismatch := false
+	for _, rt := range routes {
+		p, ok := match(rt.pattern, path)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			methodMismatch = true
+			continue
+		}
+
+		s.mu.Lock()
+		rt.handle(s, w, r, p)
+		s.mu.Unlock()
+		return
+	}
+
+	if methodMismatch {
+		writeError(w, http.StatusMethodNotAllowed, "405 Method Not Allowed")
+		return
+	}
+	writeError(w, http.StatusNotFound, "404 Not Found")
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token := r.URL.Query().Get("access_token")
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		token = strings.TrimSpace(auth[strings.Index(auth, " ")+1:])
+	}
+	return token == s.Token
+}
+
+// params are the parameters of a matched route.
+type params map[string]string
+
+// match matches a path against a pattern like "repos/:owner/:repo", a
+// trailing "*name" matches the rest of the path, which may be empty.
+func match(pattern, path string) (params, bool) {
+	pat := strings.Split(pattern, "/")
+	segs := strings.Split(path, "/")
+	if path == "" {
+		segs = nil
+	}
+
+	p := make(params)
+	for i, ps := range pat {
+		if strings.HasPrefix(ps, "*") {
+			if i < len(segs) {
+				p[ps[1:]] = strings.Join(segs[i:], "/")
+			} else {
+				p[ps[1:]] = ""
+			}
+			return p, true
+		}
+		if i >= len(segs) {
+			return nil, false
+		}
+		if strings.HasPrefix(ps, ":") {
+			v, err := url.PathUnescape(segs[i])
+			if err != nil {
+				return nil, false
+			}
+			p[ps[1:]] = v
+		} else if ps != segs[i] {
+			return nil, false
+		}
+	}
+	if len(pat) != len(segs) {
+		return nil, false
+	}
+	return p, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "400 Bad Request: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writePage writes the page of items, a slice, asked for by the page and
+// per_page parameters, with gitee's pagination headers.
+func writePage(w http.ResponseWriter, r *http.Request, items interface{}) {
+	v := reflect.ValueOf(items)
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if perPage < 1 {
+		perPage = 20
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	total := v.Len()
+	totalPage := (total + perPage - 1) / perPage
+	w.Header().Set("Total_count", strconv.Itoa(total))
+	w.Header().Set("Total_page", strconv.Itoa(totalPage))
+
+	link := func(page int, rel string) string {
+		u := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path}
+		lq := r.URL.Query()
+		lq.Set("page", strconv.Itoa(page))
+		lq.Set("per_page", strconv.Itoa(perPage))
+		u.RawQuery = lq.Encode()
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+	}
+	var links []string
+	if page < totalPage {
+		links = append(links, link(page+1, "next"), link(totalPage, "last"))
+	}
+	if page > 1 {
+		links = append(links, link(1, "first"), link(page-1, "prev"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+	writeJSON(w, http.StatusOK, v.Slice(start, end).Interface())
+}
@@ -0,0 +1,163 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Package oauth provides gitee's OAuth2 endpoints and scopes for
+// golang.org/x/oauth2, the password grant gitee supports on /oauth/token, and
+// token sources refreshing the access token automatically.
+//
+//  cfg := oauth.NewConfig("client id", "client secret", "https://example.com/callback",
+//  	oauth.ScopeUserInfo, oauth.ScopeProjects)
+//  ts := oauth.PasswordTokenSource(ctx, cfg, "username", "password")
+//  client := oauth.NewClient(ctx, ts)
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+	"golang.org/x/oauth2"
+)
+
+// Endpoint is gitee's OAuth2 endpoint. gitee 要求 client_id 和 client_secret
+// 放在参数里面, 不支持 HTTP Basic.
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://gitee.com/oauth/authorize",
+	TokenURL:  "https://gitee.com/oauth/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+// NewEndpoint returns the OAuth2 endpoint of the gitee site at webURL, for
+// self-hosted deployments, e.g. NewEndpoint(client.WebURL) of a client from
+// gitee.NewEnterpriseClient.
+func NewEndpoint(webURL *url.URL) oauth2.Endpoint {
+	authURL, _ := webURL.Parse("oauth/authorize")
+	tokenURL, _ := webURL.Parse("oauth/token")
+	return oauth2.Endpoint{
+		AuthURL:   authURL.String(),
+		TokenURL:  tokenURL.String(),
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+}
+
+// Scope is a gitee OAuth2 scope.
+type Scope string
+
+const (
+	ScopeUserInfo     Scope = "user_info"     // 访问用户的个人信息、最新动态等
+	ScopeProjects     Scope = "projects"      // 查看、创建、更新用户的项目
+	ScopePullRequests Scope = "pull_requests" // 查看、发布、更新用户的 Pull Request
+	ScopeIssues       Scope = "issues"        // 查看、发布、更新用户的 Issue
+	ScopeNotes        Scope = "notes"         // 查看、发布、管理用户在项目、代码片段中的评论
+	ScopeKeys         Scope = "keys"          // 查看、部署、删除用户的公钥
+	ScopeHook         Scope = "hook"          // 查看、部署、更新用户的 Webhook
+	ScopeGroups       Scope = "groups"        // 查看、管理用户的组织以及成员
+	ScopeGists        Scope = "gists"         // 查看、删除、更新用户的代码片段
+	ScopeEnterprises  Scope = "enterprises"   // 查看、管理用户的企业以及成员
+	ScopeEmails       Scope = "emails"        // 查看用户的个人邮箱信息
+)
+
+// AllScopes lists every gitee scope.
+var AllScopes = []Scope{
+	ScopeUserInfo, ScopeProjects, ScopePullRequests, ScopeIssues, ScopeNotes, ScopeKeys,
+	ScopeHook, ScopeGroups, ScopeGists, ScopeEnterprises, ScopeEmails,
+}
+
+// Strings returns scopes as strings, as oauth2.Config.Scopes wants them.
+func Strings(scopes ...Scope) []string {
+	s := make([]string, len(scopes))
+	for i, scope := range scopes {
+		s[i] = string(scope)
+	}
+	return s
+}
+
+// NewConfig returns an oauth2.Config for a gitee OAuth application using
+// Endpoint. Without scopes gitee grants user_info only.
+func NewConfig(clientID, clientSecret, redirectURL string, scopes ...Scope) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Endpoint:     Endpoint,
+		Scopes:       Strings(scopes...),
+	}
+}
+
+// PasswordLogin gets a token for username and password with the password
+// grant. The client ID and secret of cfg are required by gitee as well.
+func PasswordLogin(ctx context.Context, cfg *oauth2.Config, username, password string) (*oauth2.Token, error) {
+	return cfg.PasswordCredentialsToken(ctx, username, password)
+}
+
+// TokenSource returns a token source starting with tok that refreshes the
+// access token with the refresh token once it expires. gitee 的 access_token
+// 有效期是一天.
+func TokenSource(ctx context.Context, cfg *oauth2.Config, tok *oauth2.Token) oauth2.TokenSource {
+	return cfg.TokenSource(ctx, tok)
+}
+
+// PasswordTokenSource returns a token source that logs in with username and
+// password on first use, refreshes the access token once it expires and logs
+// in again when the refresh token is no longer accepted.
+func PasswordTokenSource(ctx context.Context, cfg *oauth2.Config, username, password string) oauth2.TokenSource {
+	return &passwordTokenSource{ctx: ctx, cfg: cfg, username: username, password: password}
+}
+
+type passwordTokenSource struct {
+	ctx      context.Context
+	cfg      *oauth2.Config
+	username string
+	password string
+
+	mu  sync.Mutex
+	tok *oauth2.Token
+}
+
+// Token implements the oauth2.TokenSource interface.
+func (s *passwordTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tok.Valid() {
+		return s.tok, nil
+	}
+
+	if s.tok != nil && s.tok.RefreshToken != "" {
+		tok, err := s.cfg.TokenSource(s.ctx, s.tok).Token()
+		if err == nil {
+			s.tok = tok
+			return tok, nil
+		}
+		var rerr *oauth2.RetrieveError
+		if !errors.As(err, &rerr) {
+			return nil, err
+		}
+		// refresh_token 失效了, 重新用密码登录
+	}
+
+	tok, err := PasswordLogin(s.ctx, s.cfg, s.username, s.password)
+	if err != nil {
+		return nil, err
+	}
+	s.tok = tok
+	return tok, nil
+}
+
+// NewClient returns a gitee client authenticating with tokens from ts.
+func NewClient(ctx context.Context, ts oauth2.TokenSource) *gitee.Client {
+	return gitee.NewClient(oauth2.NewClient(ctx, ts))
+}
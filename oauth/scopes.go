@@ -0,0 +1,212 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+	"golang.org/x/oauth2"
+)
+
+// Operation is something a program intends to do with a token, used to work
+// out the scopes it needs.
+type Operation string
+
+const (
+	OpReadUser             Operation = "read user"
+	OpReadRepo             Operation = "read repo"
+	OpCreateRepo           Operation = "create repo"
+	OpEditRepo             Operation = "edit repo"
+	OpDeleteRepo           Operation = "delete repo"
+	OpPushContents         Operation = "push contents"
+	OpManageHooks          Operation = "manage hooks"
+	OpManageKeys           Operation = "manage keys"
+	OpManageIssues         Operation = "manage issues"
+	OpManagePullRequests   Operation = "manage pull requests"
+	OpComment              Operation = "comment"
+	OpManageGists          Operation = "manage gists"
+	OpEditOrg              Operation = "edit org"
+	OpEditOrgMembers       Operation = "edit org members"
+	OpEditEnterpriseMember Operation = "edit enterprise members"
+	OpReadEmails           Operation = "read emails"
+)
+
+// operationScopes maps every operation to the scopes it needs besides
+// user_info, which gitee requires for every call.
+var operationScopes = map[Operation][]Scope{
+	OpReadUser:             nil,
+	OpReadRepo:             {ScopeProjects},
+	OpCreateRepo:           {ScopeProjects},
+	OpEditRepo:             {ScopeProjects},
+	OpDeleteRepo:           {ScopeProjects},
+	OpPushContents:         {ScopeProjects},
+	OpManageHooks:          {ScopeProjects, ScopeHook},
+	OpManageKeys:           {ScopeKeys},
+	OpManageIssues:         {ScopeIssues},
+	OpManagePullRequests:   {ScopeProjects, ScopePullRequests},
+	OpComment:              {ScopeNotes},
+	OpManageGists:          {ScopeGists},
+	OpEditOrg:              {ScopeGroups},
+	OpEditOrgMembers:       {ScopeGroups},
+	OpEditEnterpriseMember: {ScopeEnterprises},
+	OpReadEmails:           {ScopeEmails},
+}
+
+// RequiredScopes returns the scopes needed for ops, in the order of
+// AllScopes. Unknown operations need user_info only.
+func RequiredScopes(ops ...Operation) []Scope {
+	need := map[Scope]bool{ScopeUserInfo: true}
+	for _, op := range ops {
+		for _, scope := range operationScopes[op] {
+			need[scope] = true
+		}
+	}
+
+	var scopes []Scope
+	for _, scope := range AllScopes {
+		if need[scope] {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// ScopesFromToken returns the scopes gitee granted with tok, from the
+// "scope" field of the token response.
+func ScopesFromToken(tok *oauth2.Token) []Scope {
+	s, _ := tok.Extra("scope").(string)
+	return parseScopes(s)
+}
+
+func parseScopes(s string) []Scope {
+	var scopes []Scope
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' }) {
+		scopes = append(scopes, Scope(f))
+	}
+	return scopes
+}
+
+// CheckResult is the result of checking a token against operations.
+type CheckResult struct {
+	Required []Scope // scopes the operations need
+	Granted  []Scope // scopes the token has, as far as they could be checked
+	Missing  []Scope // required scopes the token does not have
+
+	// Unverified are required scopes that cannot be checked without a
+	// mutating call, e.g. hook and notes when probing the token.
+	Unverified []Scope
+}
+
+// OK reports whether no required scope is missing.
+func (r *CheckResult) OK() bool {
+	return len(r.Missing) == 0
+}
+
+// CheckScopes checks granted scopes against the scopes ops need.
+func CheckScopes(granted []Scope, ops ...Operation) *CheckResult {
+	return checkScopes(granted, nil, ops)
+}
+
+func checkScopes(granted, unverified []Scope, ops []Operation) *CheckResult {
+	has := make(map[Scope]bool)
+	for _, scope := range granted {
+		has[scope] = true
+	}
+	unknown := make(map[Scope]bool)
+	for _, scope := range unverified {
+		unknown[scope] = true
+	}
+
+	r := &CheckResult{Required: RequiredScopes(ops...), Granted: granted}
+	for _, scope := range r.Required {
+		switch {
+		case has[scope]:
+		case unknown[scope]:
+			r.Unverified = append(r.Unverified, scope)
+		default:
+			r.Missing = append(r.Missing, scope)
+		}
+	}
+	return r
+}
+
+// scopeProbes are read-only requests telling whether the token has a scope.
+// pull_requests, notes 和 hook 没有不需要仓库的只读接口, 检查不了.
+var scopeProbes = []struct {
+	scope Scope
+	url   string
+}{
+	{ScopeUserInfo, "user"},
+	{ScopeProjects, "user/repos?per_page=1"},
+	{ScopeIssues, "user/issues?per_page=1"},
+	{ScopeKeys, "user/keys?per_page=1"},
+	{ScopeGroups, "user/orgs?per_page=1"},
+	{ScopeGists, "gists?per_page=1"},
+	{ScopeEnterprises, "user/enterprises?per_page=1"},
+	{ScopeEmails, "emails"},
+}
+
+// headerOAuthScopes lists the scopes of the token, if the server sends it.
+const headerOAuthScopes = "X-OAuth-Scopes"
+
+// TokenScopes finds out the scopes of the token client authenticates with.
+// It uses the X-OAuth-Scopes header if the server sends one, else it probes
+// read-only endpoints. The scopes it cannot check this way, including the ones
+// whose endpoint answers 404 or 5xx, are returned as unverified.
+func TokenScopes(ctx context.Context, client *gitee.Client) (granted, unverified []Scope, err error) {
+	for i, probe := range scopeProbes {
+		req, err := client.NewRequest("GET", probe.url, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		resp, err := client.Do(ctx, req, nil)
+		if i == 0 && resp != nil {
+			if h, ok := resp.Header[http.CanonicalHeaderKey(headerOAuthScopes)]; ok {
+				return parseScopes(strings.Join(h, " ")), nil, nil
+			}
+		}
+		switch {
+		case err == nil:
+			granted = append(granted, probe.scope)
+		case i > 0 && (gitee.IsForbidden(err) || gitee.IsUnauthorized(err)):
+			// 没有这个 scope
+		case i > 0 && (gitee.IsNotFound(err) || gitee.IsServerError(err)):
+			// 私有部署可能没有这个接口, 或者接口临时出错, 检查不了
+			unverified = append(unverified, probe.scope)
+		default:
+			return nil, nil, err
+		}
+	}
+	return granted, append(unverified, ScopePullRequests, ScopeNotes, ScopeHook), nil
+}
+
+// Check works out the scopes ops need and checks them against the token
+// client authenticates with, using read-only requests only, so a long
+// migration can fail early instead of half-way.
+//
+//  r, err := oauth.Check(ctx, client, oauth.OpCreateRepo, oauth.OpManageHooks, oauth.OpEditOrgMembers)
+//  if err == nil && !r.OK() {
+//  	log.Fatalf("token is missing scopes %v", r.Missing)
+//  }
+func Check(ctx context.Context, client *gitee.Client, ops ...Operation) (*CheckResult, error) {
+	granted, unverified, err := TokenScopes(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return checkScopes(granted, unverified, ops), nil
+}
@@ -0,0 +1,309 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Package recorder provides an http.RoundTripper recording gitee API calls
+// to a cassette file and replaying them, so tests written against the live
+// API can run offline and deterministically. Tokens, passwords and client
+// secrets are scrubbed before anything is written.
+//
+//  rec, err := recorder.New("testdata/gitee.cassette.json", recorder.ModeReplay, nil)
+//  client := gitee.NewClient(rec.Client())
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette only, never touching
+	// the network. Requests not in the cassette fail with ErrNoInteraction.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the network and writes them to the
+	// cassette, replacing what was recorded before.
+	ModeRecord
+	// ModePassthrough sends requests to the network without recording.
+	ModePassthrough
+)
+
+// ErrNoInteraction is returned in ModeReplay for requests that are not in
+// the cassette.
+var ErrNoInteraction = errors.New("recorder: request not found in cassette")
+
+// Redacted replaces the secrets scrubbed from cassettes.
+const Redacted = "REDACTED"
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response. Body is base64 encoded if BodyEncoding
+// is "base64", e.g. for archives.
+type Response struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// Recorder is an http.RoundTripper recording or replaying interactions.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New returns a Recorder using the cassette file at path. In ModeReplay the
+// file must exist. transport is used for the network in ModeRecord and
+// ModePassthrough, default is http.DefaultTransport.
+func New(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, transport: transport, cassette: &Cassette{}}
+
+	if mode == ModeReplay {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, r.cassette); err != nil {
+			return nil, fmt.Errorf("recorder: %s: %v", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an *http.Client using the recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModePassthrough {
+		return r.transport.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	recorded := Request{Method: req.Method, URL: scrubURL(req.URL), Body: scrubBody(string(body))}
+
+	if r.mode == ModeReplay {
+		i, err := r.find(recorded)
+		if err != nil {
+			return nil, err
+		}
+		return i.Response.httpResponse(req)
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	i := &Interaction{Request: recorded, Response: newResponse(resp, respBody)}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	err = r.save()
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// find returns the first unused interaction matching req. Once all matching
+// interactions were used the last one is returned again, so repeated calls
+// replay like polling the same state.
+func (r *Recorder) find(req Request) (*Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for n, i := range r.cassette.Interactions {
+		if i.Request != req {
+			continue
+		}
+		if !r.used[n] {
+			r.used[n] = true
+			return i, nil
+		}
+		last = n
+	}
+	if last >= 0 {
+		return r.cassette.Interactions[last], nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+}
+
+// save writes the cassette, r.mu must be held. 每次录制都写文件,
+// 测试进程中途退出也不会丢.
+func (r *Recorder) save() error {
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+func newResponse(resp *http.Response, body []byte) Response {
+	header := scrubHeader(resp.Header)
+	header.Del("Date")
+
+	recorded := Response{StatusCode: resp.StatusCode, Header: header}
+	if utf8.Valid(body) {
+		recorded.Body = scrubBody(string(body))
+	} else {
+		recorded.Body = base64.StdEncoding.EncodeToString(body)
+		recorded.BodyEncoding = "base64"
+	}
+	return recorded
+}
+
+func (r Response) httpResponse(req *http.Request) (*http.Response, error) {
+	body := []byte(r.Body)
+	if r.BodyEncoding == "base64" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(r.Body); err != nil {
+			return nil, err
+		}
+	}
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// secretParams are the parameters scrubbed from URLs and bodies.
+var secretParams = []string{"access_token", "refresh_token", "client_secret", "password"}
+
+// scrubURL returns u with the secret query parameters redacted and the
+// parameters sorted, so that the URL can be matched on replay.
+func scrubURL(u *url.URL) string {
+	c := *u
+	q := c.Query()
+	for _, p := range secretParams {
+		if _, ok := q[p]; ok {
+			q.Set(p, Redacted)
+		}
+	}
+	c.RawQuery = q.Encode() // Encode 会按 key 排序
+	return c.String()
+}
+
+// secretHeaders are the response headers whose values are redacted.
+var secretHeaders = []string{"Set-Cookie", "Authorization", "Proxy-Authorization", "Www-Authenticate", "X-Gitee-Token"}
+
+var (
+	jsonSecrets   = regexp.MustCompile(`("(?:` + strings.Join(secretParams, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	formSecrets   = regexp.MustCompile(`(^|&)(` + strings.Join(secretParams, "|") + `)=[^&]*`)
+	headerSecrets = regexp.MustCompile(`([?&;]|^)(` + strings.Join(secretParams, "|") + `)=[^&;>,\s]*`)
+)
+
+// scrubHeader returns a copy of h with the secret headers redacted, and the
+// secret parameters of URLs in the other headers, e.g. Link, redacted.
+func scrubHeader(h http.Header) http.Header {
+	header := h.Clone()
+	if header == nil {
+		return make(http.Header)
+	}
+	for _, k := range secretHeaders {
+		if values, ok := header[k]; ok {
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+	}
+	for _, values := range header {
+		for i, v := range values {
+			values[i] = headerSecrets.ReplaceAllString(v, `${1}${2}=`+Redacted)
+		}
+	}
+	return header
+}
+
+// scrubBody redacts the secrets of a JSON or form encoded body.
+func scrubBody(body string) string {
+	body = jsonSecrets.ReplaceAllString(body, `${1}"`+Redacted+`"`)
+	return formSecrets.ReplaceAllString(body, `${1}${2}=`+Redacted)
+}
@@ -0,0 +1,154 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/mamh-mixed/go-gitee/gitee"
+	"net/http"
+	"testing"
+)
+
+func TestListRepositoryEvents(t *testing.T) {
+	skipOffline(t)
+	opts := &gitee.EventListOptions{
+		Limit: 20,
+	}
+	events, response, err := client.Activity.ListRepositoryEvents(ctx, "mamh-mixed", "go-gitee", opts)
+	for index, event := range events {
+		payload, err := event.ParsePayload()
+		fmt.Println(index, *event.Type, payload, err)
+	}
+	fmt.Println(response)
+	fmt.Println(err)
+}
+
+func TestEventParsePayload(t *testing.T) {
+	raw := json.RawMessage(`{"ref":"refs/heads/master","before":"0000","after":"8896","size":1,
+		"commits":[{"sha":"8896","message":"init","author":{"name":"mamh","email":"mamh@example.com"}}]}`)
+	event := &gitee.Event{
+		Type:       gitee.String("PushEvent"),
+		RawPayload: &raw,
+	}
+
+	payload, err := event.ParsePayload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	push, ok := payload.(*gitee.PushEvent)
+	if !ok {
+		t.Fatalf("ParsePayload returned %T, want *gitee.PushEvent", payload)
+	}
+	if *push.Ref != "refs/heads/master" || len(push.Commits) != 1 || *push.Commits[0].SHA != "8896" {
+		t.Errorf("ParsePayload returned %v", push)
+	}
+}
+
+func TestListNotifications(t *testing.T) {
+	skipOffline(t)
+	opts := &gitee.NotificationListOptions{
+		Unread: true,
+		Type:   "referer",
+	}
+	notifications, response, err := client.Activity.ListNotifications(ctx, opts)
+	for index, n := range notifications {
+		fmt.Println(index, *n.ID, *n.Content)
+	}
+	fmt.Println(response)
+	fmt.Println(err)
+
+	count, response, err := client.Activity.CountNotifications(ctx, &gitee.NotificationCountOptions{Unread: true})
+	fmt.Println(count)
+	fmt.Println(response)
+	fmt.Println(err)
+}
+
+func TestStarred(t *testing.T) {
+	skipOffline(t)
+	starred, response, err := client.Activity.IsStarred(ctx, "mamh-mixed", "go-gitee")
+	fmt.Println(starred)
+	fmt.Println(response)
+	fmt.Println(err)
+
+	opts := &gitee.ActivityListStarredOptions{
+		Sort:  "last_push",
+		Limit: 20,
+	}
+	repos, response, err := client.Activity.ListStarred(ctx, "", opts)
+	for index, repo := range repos {
+		fmt.Println(index, *repo.FullName)
+	}
+	fmt.Println(response)
+	fmt.Println(err)
+}
+
+func TestMessages(t *testing.T) {
+	var method, path, query string
+	var body map[string]interface{}
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, query = r.Method, r.URL.Path, r.URL.RawQuery
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch {
+		case r.Method == "GET":
+			w.Write([]byte(`{"total_count":2,"list":[{"id":1,"content":"hi","unread":true},{"id":2,"content":"yo"}]}`))
+		case r.Method == "POST":
+			w.Write([]byte(`{"id":3,"content":"hello","sender":{"login":"mamh"}}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+
+	messages, _, err := c.Activity.ListMessages(ctx, &gitee.MessageListOptions{Unread: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/api/v5/notifications/messages" || query != "unread=true" {
+		t.Errorf("ListMessages requested %v?%v", path, query)
+	}
+	if len(messages) != 2 || *messages[0].Content != "hi" || !*messages[0].Unread || *messages[1].ID != 2 {
+		t.Errorf("ListMessages returned %v", messages)
+	}
+
+	message, _, err := c.Activity.SendMessage(ctx, &gitee.MessageSendRequest{
+		Username: gitee.String("mamh"),
+		Content:  gitee.String("hello"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "POST" || path != "/api/v5/notifications/messages" || body["username"] != "mamh" || body["content"] != "hello" {
+		t.Errorf("SendMessage sent %v %v %v", method, path, body)
+	}
+	if *message.ID != 3 || *message.Sender.Login != "mamh" {
+		t.Errorf("SendMessage returned %v", message)
+	}
+
+	if _, err := c.Activity.MarkMessagesRead(ctx, &gitee.MarkReadRequest{IDs: gitee.String("1,2")}); err != nil {
+		t.Fatal(err)
+	}
+	if method != "PUT" || path != "/api/v5/notifications/messages" || body["ids"] != "1,2" {
+		t.Errorf("MarkMessagesRead sent %v %v %v", method, path, body)
+	}
+
+	if _, err := c.Activity.MarkMessageRead(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if method != "PATCH" || path != "/api/v5/notifications/messages/1" {
+		t.Errorf("MarkMessageRead sent %v %v", method, path)
+	}
+}
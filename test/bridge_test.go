@@ -0,0 +1,236 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/mamh-mixed/go-gitee/bridge"
+	"github.com/mamh-mixed/go-gitee/gitee"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBridgeRender(t *testing.T) {
+	tests := []struct {
+		eventType string
+		file      string
+		title     string
+		text      string
+	}{
+		{
+			gitee.PushHookEventType, "push.json",
+			"[mamh-mixed/go-gitee] mamh 推送了 1 个提交到 master",
+			"**mamh** 推送到 [mamh-mixed/go-gitee](https://gitee.com/mamh-mixed/go-gitee) 的 **master** 分支\n\n" +
+				"- [8896821c](https://gitee.com/mamh-mixed/go-gitee/commit/8896821c53eda6698ef5c75ba5182e547e8476f1) update README.md - mamh\n\n" +
+				"[查看变更](https://gitee.com/mamh-mixed/go-gitee/compare/c764302e6da151e08608c08ab30e986b04b9064b...8896821c53eda6698ef5c75ba5182e547e8476f1)",
+		},
+		{
+			gitee.TagPushHookEventType, "tag_push.json",
+			"[mamh-mixed/go-gitee] mamh 推送了标签 v1.0.0",
+			"**mamh** 在 [mamh-mixed/go-gitee](https://gitee.com/mamh-mixed/go-gitee) 推送了标签 **v1.0.0**",
+		},
+		{
+			gitee.IssueHookEventType, "issue.json",
+			"[mamh-mixed/go-gitee] Issue I5XYZ1 open: ListCommits 分页不对",
+			"**mamh** open Issue [#I5XYZ1 ListCommits 分页不对](https://gitee.com/mamh-mixed/go-gitee/issues/I5XYZ1)\n\n" +
+				"> 状态: 待办的\n> 负责人: mamh",
+		},
+		{
+			gitee.NoteHookEventType, "note.json",
+			"[mamh-mixed/go-gitee] mamh 评论了 Issue ListCommits 分页不对",
+			"**mamh** 评论了 Issue [ListCommits 分页不对](https://gitee.com/mamh-mixed/go-gitee/issues/I5XYZ1#note_13451234)\n\n" +
+				"> 已修复，请看 #1",
+		},
+		{
+			gitee.MergeRequestHookEventType, "merge_request.json",
+			"[mamh-mixed/go-gitee] Pull Request !1 open: 修复 ListCommits 分页",
+			"**mamh** open Pull Request [!1 修复 ListCommits 分页](https://gitee.com/mamh-mixed/go-gitee/pulls/1)\n\n" +
+				"> fix-pagination → master",
+		},
+	}
+	r := bridge.NewRenderer()
+	for _, tt := range tests {
+		payload, err := ioutil.ReadFile("../cmd/hooksim/fixtures/" + tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		event, err := gitee.ParseWebHook(tt.eventType, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := r.Render(event)
+		if err != nil {
+			t.Fatalf("%v: Render returned error %v", tt.eventType, err)
+		}
+		if msg.Title != tt.title {
+			t.Errorf("%v: title = %q, want %q", tt.eventType, msg.Title, tt.title)
+		}
+		if msg.Text != tt.text {
+			t.Errorf("%v: text = %q, want %q", tt.eventType, msg.Text, tt.text)
+		}
+	}
+
+	msg, _ := r.Render(&gitee.TagPushHookEvent{
+		Ref:      gitee.String("refs/tags/v1.0.0"),
+		UserName: gitee.String("mamh"),
+		Deleted:  gitee.Bool(true),
+	})
+	if msg.Title != "mamh 删除了标签 v1.0.0" {
+		t.Errorf("Render of deleted tag returned title %q", msg.Title)
+	}
+
+	if err := r.SetTemplate(gitee.PushHookEventType, `{{ref .Ref}}`, `{{short .After}}`); err != nil {
+		t.Fatal(err)
+	}
+	msg, _ = r.Render(&gitee.PushHookEvent{
+		Ref:   gitee.String("refs/heads/dev"),
+		After: gitee.String("8896821c53eda6698ef5c75ba5182e547e8476f1"),
+	})
+	if msg.Title != "dev" || msg.Text != "8896821c" {
+		t.Errorf("Render with custom template returned %+v", msg)
+	}
+}
+
+func TestBridgeRobots(t *testing.T) {
+	secret := "SECxxxx"
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make(map[string]interface{})
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+
+		switch r.URL.Path {
+		case "/robot/send": // 钉钉: 签名在 url 参数里面
+			q := r.URL.Query()
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write([]byte(q.Get("timestamp") + "\n" + secret))
+			if q.Get("sign") != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+				w.Write([]byte(`{"errcode":310000,"errmsg":"sign not match"}`))
+				return
+			}
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case "/open-apis/bot/v2/hook/token": // 飞书: 签名在 body 里面
+			mac := hmac.New(sha256.New, []byte(body["timestamp"].(string)+"\n"+secret))
+			if body["sign"] != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+				w.Write([]byte(`{"code":19021,"msg":"sign match fail"}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"msg":"success"}`))
+		default:
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	b := bridge.New(bridge.NewRenderer(),
+		&bridge.DingTalk{Webhook: server.URL + "/robot/send?access_token=token", Secret: secret},
+		&bridge.Feishu{Webhook: server.URL + "/open-apis/bot/v2/hook/token", Secret: secret},
+		&bridge.WeCom{Webhook: server.URL + "/cgi-bin/webhook/send?key=token"},
+	)
+	event := &gitee.IssueHookEvent{
+		Action:     gitee.String("open"),
+		Issue:      &gitee.HookIssue{Number: gitee.String("I5XYZ1"), Title: gitee.String("bug")},
+		Repository: &gitee.HookRepository{FullName: gitee.String("mamh-mixed/go-gitee")},
+		Sender:     &gitee.HookUser{Name: gitee.String("mamh")},
+	}
+	if err := b.Forward(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 3 || bodies[0]["msgtype"] != "markdown" || bodies[1]["msg_type"] != "interactive" {
+		t.Errorf("robots received %v", bodies)
+	}
+
+	bad := &bridge.DingTalk{Webhook: server.URL + "/robot/send?access_token=token", Secret: "wrong"}
+	if err := bad.Send(ctx, &bridge.Message{Title: "t", Text: "x"}); err == nil {
+		t.Error("DingTalk.Send with wrong secret returned no error")
+	}
+}
+
+func TestBridgeMissingFields(t *testing.T) {
+	r := bridge.NewRenderer()
+	events := []interface{}{
+		&gitee.PushHookEvent{},
+		&gitee.TagPushHookEvent{},
+		&gitee.IssueHookEvent{Action: gitee.String("open")},
+		&gitee.NoteHookEvent{},
+		&gitee.MergeRequestHookEvent{Action: gitee.String("open")},
+	}
+	for _, event := range events {
+		if _, err := r.Render(event); err != nil {
+			t.Errorf("Render(%T) with missing fields returned error %v", event, err)
+		}
+	}
+}
+
+func TestBridgeSendErrors(t *testing.T) {
+	// 一个已经关掉的端口, 连接一定失败
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	var sent int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	b := bridge.New(bridge.NewRenderer(),
+		&bridge.DingTalk{Webhook: "http://" + addr + "/robot/send?access_token=secrettoken", Secret: "SECxxxx"},
+		&bridge.WeCom{Webhook: server.URL + "/cgi-bin/webhook/send?key=token"},
+		&bridge.Feishu{Webhook: "http://" + addr + "/open-apis/bot/v2/hook/secrettoken"},
+	)
+	// 模板渲染失败也要发出去, 发送失败的只写日志, 不能让 gitee 重新推送
+	var logged strings.Builder
+	b.ErrorLog = log.New(&logged, "", 0)
+	if err := b.Renderer.SetTemplate(gitee.IssueHookEventType, `{{.Issue.Title}}`, `x`); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Forward(ctx, &gitee.IssueHookEvent{}); err != nil {
+		t.Errorf("Forward returned %v, want nil", err)
+	}
+	if !strings.Contains(logged.String(), "robot 0") || !strings.Contains(logged.String(), "robot 2") {
+		t.Errorf("ErrorLog = %q, want robots 0 and 2", logged.String())
+	}
+	if sent != 1 {
+		t.Errorf("WeCom received %d messages, want 1", sent)
+	}
+
+	err = b.Send(ctx, &bridge.Message{Title: "t", Text: "x"})
+	var serr *bridge.SendError
+	if !errors.As(err, &serr) {
+		t.Fatalf("Send returned %v, want *bridge.SendError", err)
+	}
+	if robots := serr.Robots(); len(robots) != 2 || robots[0] != 0 || robots[1] != 2 {
+		t.Errorf("failed robots = %v, want [0 2]", robots)
+	}
+	for _, secret := range []string{"secrettoken", "timestamp=1"} {
+		if strings.Contains(err.Error(), secret) || strings.Contains(logged.String(), secret) {
+			t.Errorf("error %q or log contains %q", err, secret)
+		}
+	}
+}
@@ -0,0 +1,515 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package test
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/mamh-mixed/go-gitee/gitee"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client talking to a local server serving handler.
+func newTestClient(t *testing.T, handler http.Handler) *gitee.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := gitee.NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/api/v5/")
+	return c
+}
+
+func TestRateLimit(t *testing.T) {
+	var requests int
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/api/v5/users/mamh" {
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.Write([]byte(`{"login":"mamh"}`))
+			return
+		}
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"Too Many Requests"}`))
+	}))
+
+	_, resp, err := c.Users.Get(ctx, "mamh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Rate.Limit != 5000 || resp.Rate.Remaining != 4999 || c.RateLimit().Limit != 5000 {
+		t.Errorf("Response.Rate = %v, Client.RateLimit() = %v", resp.Rate, c.RateLimit())
+	}
+
+	_, _, err = c.Users.Get(ctx, "throttled")
+	var rerr *gitee.RateLimitError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("Get returned error %v, want *RateLimitError", err)
+	}
+	if rerr.Rate.Reset.IsZero() {
+		t.Errorf("RateLimitError.Rate.Reset is zero, want parsed from Retry-After")
+	}
+
+	// 被限流之后, 恢复之前不应该再发请求了
+	_, _, err = c.Users.Get(ctx, "mamh")
+	if !errors.As(err, &rerr) || requests != 2 {
+		t.Errorf("Get after rate limit returned %v and made %d requests, want *RateLimitError and 2", err, requests)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var requests int
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests%3 != 0 { // 每 3 次请求只有最后一次成功
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"login":"mamh"}`))
+	}))
+	c.RetryPolicy = &gitee.RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+	}
+
+	user, _, err := c.Users.Get(ctx, "mamh")
+	if err != nil || *user.Login != "mamh" || requests != 3 {
+		t.Errorf("Get returned %v, %v after %d requests, want success after 3", user, err, requests)
+	}
+
+	// 单次调用关闭重试
+	requests = 0
+	_, resp, err := c.Users.Get(gitee.WithRetryPolicy(ctx, &gitee.RetryPolicy{}), "mamh")
+	if err == nil || resp.StatusCode != http.StatusBadGateway || requests != 1 {
+		t.Errorf("Get without retries returned %v after %d requests", err, requests)
+	}
+
+	// POST 默认不重试
+	requests = 0
+	_, _, err = c.Users.CreateKey(ctx, &gitee.KeyCreateRequest{Key: gitee.String("ssh-rsa AAAA"), Title: gitee.String("test")})
+	if err == nil || requests != 1 {
+		t.Errorf("CreateKey returned %v after %d requests, want an error after 1", err, requests)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		// gitee 一般只返回 total_page, 不一定有 Link
+		w.Header().Set("Total_page", "3")
+		fmt.Fprintf(w, `[{"sha":"%s-a"},{"sha":"%s-b"}]`, page, page)
+	}))
+
+	commits, err := c.Repositories.ListAllCommits(ctx, "mamh", "go-gitee", &gitee.CommitsListOptions{ListOptions: gitee.ListOptions{PerPage: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 6 || *commits[0].SHA != "1-a" || *commits[5].SHA != "3-b" {
+		t.Errorf("ListAllCommits returned %v, want 6 commits of pages 1..3", commits)
+	}
+
+	var pages int
+	opts := &gitee.CommitsListOptions{}
+	err = gitee.Paginate(ctx, &opts.ListOptions, func(ctx context.Context) (*gitee.Response, error) {
+		pages++
+		_, resp, err := c.Repositories.ListCommits(ctx, "mamh", "go-gitee", opts)
+		if pages == 2 {
+			return resp, gitee.ErrStopPagination
+		}
+		return resp, err
+	})
+	if err != nil || pages != 2 || opts.Page != 2 {
+		t.Errorf("Paginate stopped with %v after %d pages at page %d, want nil, 2, 2", err, pages, opts.Page)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	pages = 0
+	err = gitee.Paginate(cctx, &gitee.ListOptions{}, func(ctx context.Context) (*gitee.Response, error) {
+		pages++
+		cancel()
+		_, resp, err := c.Repositories.ListCommits(ctx, "mamh", "go-gitee", nil)
+		return resp, err
+	})
+	if !errors.Is(err, context.Canceled) || pages != 1 {
+		t.Errorf("Paginate returned %v after %d pages, want context.Canceled after 1", err, pages)
+	}
+
+	// 没有 Response 也没有错误就结束
+	err = gitee.Paginate(ctx, &gitee.ListOptions{}, func(ctx context.Context) (*gitee.Response, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Errorf("Paginate with nil Response returned %v, want nil", err)
+	}
+	err = gitee.FetchPages(ctx, nil, 0, func(ctx context.Context, page int) (*gitee.Response, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Errorf("FetchPages with nil Response returned %v, want nil", err)
+	}
+}
+
+func TestFetchPages(t *testing.T) {
+	var mu sync.Mutex
+	var inflight, maxInflight int
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inflight++
+		if inflight > maxInflight {
+			maxInflight = inflight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inflight--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		page := r.URL.Query().Get("page")
+		w.Header().Set("Total_page", "10")
+		if page == "7" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"boom"}`))
+			return
+		}
+		fmt.Fprintf(w, `[{"full_name":"org/repo-%s"}]`, page)
+	}))
+
+	repos, err := c.Repositories.FetchAllOrganizations(ctx, "org", nil, 3)
+	var perr *gitee.PageError
+	if !errors.As(err, &perr) || len(perr.Pages()) != 1 || perr.Pages()[0] != 7 {
+		t.Fatalf("FetchAllOrganizations returned error %v, want *PageError for page 7", err)
+	}
+	if len(repos) != 9 || *repos[0].FullName != "org/repo-1" || *repos[6].FullName != "org/repo-8" {
+		t.Errorf("FetchAllOrganizations returned %d repos, want 9 in page order", len(repos))
+	}
+	if maxInflight > 3 {
+		t.Errorf("FetchAllOrganizations made %d concurrent requests, want at most 3", maxInflight)
+	}
+}
+
+func TestCachingTransport(t *testing.T) {
+	disk, err := gitee.NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, storage := range map[string]gitee.CacheStorage{"memory": gitee.NewMemoryCache(10), "disk": disk} {
+		var requests, notModified int
+		cache := &gitee.CachingTransport{Storage: storage}
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Write([]byte(`{"login":"mamh"}`))
+		}))
+		baseURL := c.BaseURL
+		c = gitee.NewClient(cache.Client())
+		c.BaseURL = baseURL
+
+		for i := 0; i < 3; i++ {
+			user, resp, err := c.Users.Get(ctx, "mamh")
+			if err != nil {
+				t.Fatalf("%s: Get returned error: %v", name, err)
+			}
+			if *user.Login != "mamh" {
+				t.Errorf("%s: Get returned %v, want mamh", name, user)
+			}
+			if fromCache := resp.Header.Get(gitee.XFromCacheHeader) == "1"; fromCache != (i > 0) {
+				t.Errorf("%s: request %d served from cache = %v", name, i, fromCache)
+			}
+		}
+		if requests != 3 || notModified != 2 {
+			t.Errorf("%s: made %d requests with %d 304s, want 3 and 2", name, requests, notModified)
+		}
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v5/users/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"401 Unauthorized: Access token does not exist"}`))
+		case "/api/v5/users/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"没有权限执行此操作"}`))
+		case "/api/v5/users/branch":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"分支名已存在"}`))
+		case "/api/v5/users/validation":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"title is missing, head is invalid"}`))
+		case "/api/v5/users/gone":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"仓库已存在, 但你没有权限查看"}`))
+		case "/api/v5/users/server":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"message":"Bad Gateway"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+
+	tests := []struct {
+		user string
+		is   func(error) bool
+		code gitee.ErrorCode
+	}{
+		{"unauthorized", gitee.IsUnauthorized, gitee.ErrorCodeUnauthorized},
+		{"forbidden", gitee.IsForbidden, gitee.ErrorCodeNoPermission},
+		{"branch", gitee.IsConflict, gitee.ErrorCodeBranchExists},
+		{"validation", gitee.IsValidation, gitee.ErrorCodeValidation},
+		{"server", gitee.IsServerError, gitee.ErrorCodeServerError},
+		{"missing", gitee.IsNotFound, gitee.ErrorCodeNotFound},
+		{"gone", gitee.IsNotFound, gitee.ErrorCodeNotFound}, // 提示信息不能改变 404 的种类
+	}
+	for _, tt := range tests {
+		_, _, err := c.Users.Get(ctx, tt.user)
+		var eerr *gitee.ErrorResponse
+		if !tt.is(err) || !errors.As(err, &eerr) || eerr.Code != tt.code {
+			t.Errorf("Get(%q) returned %v, want code %q", tt.user, err, tt.code)
+			continue
+		}
+		if tt.user == "validation" && (len(eerr.Errors) != 2 || eerr.Errors[1].Field != "head") {
+			t.Errorf("Get(%q) returned field errors %v, want title and head", tt.user, eerr.Errors)
+		}
+		if tt.user != "missing" && tt.user != "gone" && gitee.IsNotFound(err) {
+			t.Errorf("IsNotFound(Get(%q)) = true, want false", tt.user)
+		}
+	}
+}
+
+func TestTokenTransport(t *testing.T) {
+	for _, placement := range []gitee.TokenPlacement{gitee.TokenInHeader, gitee.TokenInQuery} {
+		var got string
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL.Query().Get("access_token")
+			if auth := r.Header.Get("Authorization"); auth != "" {
+				got = strings.TrimPrefix(auth, "Bearer ")
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+		}))
+		tp := &gitee.TokenTransport{Token: "s3cret-token", Placement: placement}
+		baseURL := c.BaseURL
+		c = gitee.NewClient(tp.Client())
+		c.BaseURL = baseURL
+
+		_, _, err := c.Users.Get(ctx, "mamh")
+		if got != "s3cret-token" {
+			t.Errorf("placement %d: server got token %q, want s3cret-token", placement, got)
+		}
+		if err == nil || strings.Contains(err.Error(), "s3cret-token") {
+			t.Errorf("placement %d: Get returned error %v, want an error without the token", placement, err)
+		}
+	}
+
+	// 直接把 access_token 拼在 URL 上的也要隐藏
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	req, _ := c.NewRequest("GET", "user?access_token=s3cret-token", nil)
+	_, err := c.Do(ctx, req, nil)
+	if err == nil || strings.Contains(err.Error(), "s3cret-token") || !strings.Contains(req.URL.RawQuery, "s3cret-token") {
+		t.Errorf("Do returned error %v for %v, want the token redacted from the error only", err, req.URL)
+	}
+}
+
+func TestNewEnterpriseClient(t *testing.T) {
+	tests := []struct {
+		in, api, web string
+	}{
+		{"gitee.example.com", "https://gitee.example.com/api/v5/", "https://gitee.example.com/"},
+		{"https://gitee.example.com/", "https://gitee.example.com/api/v5/", "https://gitee.example.com/"},
+		{"https://gitee.example.com/api/v5", "https://gitee.example.com/api/v5/", "https://gitee.example.com/"},
+		{"http://10.0.0.1:8080/gitee/api/v5/", "http://10.0.0.1:8080/gitee/api/v5/", "http://10.0.0.1:8080/gitee/"},
+	}
+	for _, tt := range tests {
+		c, err := gitee.NewEnterpriseClient(tt.in, nil)
+		if err != nil {
+			t.Errorf("NewEnterpriseClient(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if c.BaseURL.String() != tt.api || c.WebURL.String() != tt.web {
+			t.Errorf("NewEnterpriseClient(%q) = %v, %v, want %v, %v", tt.in, c.BaseURL, c.WebURL, tt.api, tt.web)
+		}
+	}
+	if _, err := gitee.NewEnterpriseClient("ftp://gitee.example.com", nil); err == nil {
+		t.Errorf("NewEnterpriseClient with ftp scheme returned no error")
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"login":"mamh"}`))
+	}))
+	defer server.Close()
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	tr, err := gitee.NewEnterpriseTransport(&gitee.EnterpriseTransportOptions{CAPEM: cert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := gitee.NewEnterpriseClient(server.URL, &http.Client{Transport: tr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.Users.Get(ctx, "mamh"); err != nil {
+		t.Errorf("Get with custom CA returned error: %v", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"login":"mamh"}`))
+	}))
+	c.Timeout = 20 * time.Millisecond
+
+	start := time.Now()
+	_, _, err := c.Users.Get(ctx, "mamh")
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 150*time.Millisecond {
+		t.Errorf("Get with Client.Timeout returned %v after %v, want context.DeadlineExceeded early", err, time.Since(start))
+	}
+
+	_, _, err = c.Users.Get(gitee.WithTimeout(ctx, 0), "mamh")
+	if err != nil {
+		t.Errorf("Get with WithTimeout(0) returned error: %v", err)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, _, err = c.Users.Get(gitee.WithTimeout(cctx, time.Minute), "mamh")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Get with canceled context returned %v, want context.Canceled", err)
+	}
+
+	// 网络错误不能变成 context.Canceled
+	c.BaseURL, _ = url.Parse("http://127.0.0.1:1/api/v5/")
+	_, _, err = c.Users.Get(ctx, "mamh")
+	var uerr *url.Error
+	if !errors.As(err, &uerr) {
+		t.Errorf("Get of unreachable server returned %v, want *url.Error", err)
+	}
+}
+
+func TestObserver(t *testing.T) {
+	var requests int
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/api/v5/repos/mamh/go-gitee/pulls/1" && requests == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"number":1}`))
+	}))
+	c.RetryPolicy = &gitee.RetryPolicy{MaxAttempts: 2}
+
+	var events []*gitee.RequestEvent
+	metrics := gitee.NewPrometheusObserver("gitee")
+	c.Observer = gitee.ObserverFunc(func(ctx context.Context, e *gitee.RequestEvent) {
+		events = append(events, e)
+		metrics.ObserveRequest(ctx, e)
+	})
+
+	req, _ := c.NewRequest("GET", "repos/mamh/go-gitee/pulls/1", nil)
+	if _, err := c.Do(ctx, req, &gitee.PullRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	req, _ = c.NewRequest("GET", "user?access_token=s3cret-token", nil)
+	c.Do(ctx, req, &gitee.User{})
+
+	if len(events) != 2 {
+		t.Fatalf("Observer got %d events, want 2", len(events))
+	}
+	e := events[0]
+	if e.Path != "repos/{owner}/{repo}/pulls/{number}" || e.StatusCode != 200 || e.Attempts != 2 || e.ResponseBytes != 12 {
+		t.Errorf("Observer got %+v", e)
+	}
+	if strings.Contains(events[1].URL, "s3cret-token") {
+		t.Errorf("Observer got URL %v, want the token redacted", events[1].URL)
+	}
+
+	var b strings.Builder
+	metrics.WriteTo(&b)
+	for _, want := range []string{
+		`gitee_requests_total{method="GET",path="repos/{owner}/{repo}/pulls/{number}",status="200"} 1`,
+		`gitee_retries_total{method="GET",path="repos/{owner}/{repo}/pulls/{number}"} 1`,
+		`gitee_response_bytes_total{method="GET",path="user",status="200"} 12`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("metrics do not contain %s:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestObserverPath(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	var path string
+	c.Observer = gitee.ObserverFunc(func(ctx context.Context, e *gitee.RequestEvent) {
+		path = e.Path
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"repos/mamh/go-gitee/pulls/1", "repos/{owner}/{repo}/pulls/{number}"},
+		{"repos/mamh/go-gitee/contents/a/b.go", "repos/{owner}/{repo}/contents/{path}"},
+		{"repos/mamh/go-gitee/hooks/42", "repos/{owner}/{repo}/hooks/{id}"},
+		{"users/mamh/starred", "users/{user}/starred"},
+		{"user/starred/mamh/go-gitee", "user/starred/{owner}/{repo}"},
+		{"user/subscriptions/mamh/go-gitee", "user/subscriptions/{owner}/{repo}"},
+		{"networks/mamh/go-gitee/events", "networks/{owner}/{repo}/events"},
+		{"orgs/mamh-mixed/members", "orgs/{org}/members"},
+		{"user/memberships/orgs/acme", "user/memberships/orgs/{org}"},
+		{"user/memberships/orgs", "user/memberships/orgs"},
+		{"orgs/acme/memberships/alice", "orgs/{org}/memberships/{user}"},
+		{"enterprises/ent/members/alice", "enterprises/{enterprise}/members/{user}"},
+		{"repos/mamh/go-gitee/branches", "repos/{owner}/{repo}/branches"},
+		{"repos/mamh/go-gitee/branches/feat/x", "repos/{owner}/{repo}/branches/{branch}"},
+		{"repos/mamh/go-gitee/branches/feat/x/protection", "repos/{owner}/{repo}/branches/{branch}/protection"},
+		{"repos/mamh/go-gitee/branches/release/*/setting", "repos/{owner}/{repo}/branches/{branch}/setting"},
+	}
+	for _, tt := range tests {
+		req, _ := c.NewRequest("GET", tt.path, nil)
+		c.Do(ctx, req, nil)
+		if path != tt.want {
+			t.Errorf("path of %v = %v, want %v", tt.path, path, tt.want)
+		}
+	}
+}
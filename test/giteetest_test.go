@@ -0,0 +1,98 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+	"github.com/mamh-mixed/go-gitee/giteetest"
+)
+
+func TestGiteeTestServer(t *testing.T) {
+	srv := giteetest.NewServer()
+	defer srv.Close()
+	srv.Token = "secret"
+	srv.AddRepo(giteetest.DefaultLogin, "demo")
+	c := srv.Client()
+	ctx := context.Background()
+
+	repo, _, err := c.Repositories.Get(ctx, giteetest.DefaultLogin, "demo")
+	if err != nil || *repo.FullName != "tester/demo" {
+		t.Fatalf("Get = %v, %v", repo, err)
+	}
+
+	// 分支
+	req := &gitee.BranchRequest{Refs: gitee.String("master"), BranchName: gitee.String("dev")}
+	if _, _, err := c.Repositories.CreateBranch(ctx, "tester", "demo", req); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if _, _, err := c.Repositories.CreateBranch(ctx, "tester", "demo", req); !gitee.IsConflict(err) {
+		t.Errorf("CreateBranch again = %v, want conflict", err)
+	}
+	branches, _, err := c.Repositories.ListBranches(ctx, "tester", "demo")
+	if err != nil || len(branches) != 2 || *branches[0].Name != "dev" {
+		t.Errorf("ListBranches = %v, %v", branches, err)
+	}
+
+	// 文件
+	_, _, err = c.Repositories.CreateFile(ctx, "tester", "demo", "docs/a.txt", &gitee.RepositoryContentFileRequest{
+		Message: gitee.String("add a"), Content: []byte("hello"), Branch: gitee.String("dev"),
+	})
+	if err != nil {
+		t.Fatalf("CreateFile: %v", err)
+	}
+	opts := &gitee.RepositoryContentGetOptions{Ref: "dev"}
+	file, _, _, err := c.Repositories.GetContents(ctx, "tester", "demo", "docs/a.txt", opts)
+	if err != nil {
+		t.Fatalf("GetContents: %v", err)
+	}
+	if content, _ := file.GetContent(); content != "hello" {
+		t.Errorf("content = %q, want hello", content)
+	}
+	_, dir, _, err := c.Repositories.GetContents(ctx, "tester", "demo", "", opts)
+	if err != nil || len(dir) != 2 || *dir[1].Type != "dir" {
+		t.Errorf("GetContents dir = %v, %v", dir, err)
+	}
+	update := &gitee.RepositoryContentFileRequest{
+		Message: gitee.String("update a"), Content: []byte("bye"), Branch: gitee.String("dev"), SHA: gitee.String("stale"),
+	}
+	if _, _, err := c.Repositories.UpdateFile(ctx, "tester", "demo", "docs/a.txt", update); !gitee.IsValidation(err) {
+		t.Errorf("UpdateFile with stale sha = %v, want validation error", err)
+	}
+	update.SHA = file.SHA
+	if _, _, err := c.Repositories.UpdateFile(ctx, "tester", "demo", "docs/a.txt", update); err != nil {
+		t.Errorf("UpdateFile: %v", err)
+	}
+
+	// 分页
+	for i := 0; i < 5; i++ {
+		if _, _, err := c.Repositories.CreateHook(ctx, "tester", "demo", &gitee.HookRequest{URL: gitee.String("http://example.com")}); err != nil {
+			t.Fatalf("CreateHook: %v", err)
+		}
+	}
+	hooks, err := c.Repositories.ListAllHooks(ctx, "tester", "demo", &gitee.ListOptions{PerPage: 2})
+	if err != nil || len(hooks) != 5 {
+		t.Errorf("ListAllHooks = %d hooks, %v", len(hooks), err)
+	}
+
+	// 没有 token
+	anon := gitee.NewClient(nil)
+	anon.BaseURL = c.BaseURL
+	if _, _, err = anon.Repositories.Get(ctx, "tester", "demo"); !gitee.IsUnauthorized(err) {
+		t.Errorf("Get without token = %v, want unauthorized", err)
+	}
+}
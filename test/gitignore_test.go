@@ -6,6 +6,7 @@ import (
 )
 
 func TestListgitignore(t *testing.T) {
+	skipOffline(t)
 	rr, response, err := client.Gitignores.List(ctx)
 	fmt.Println(rr)
 	fmt.Println(response)
@@ -13,6 +14,7 @@ func TestListgitignore(t *testing.T) {
 }
 
 func TestGetGitignore(t *testing.T) {
+	skipOffline(t)
 	rr, response, err := client.Gitignores.Get(ctx, "C")
 	fmt.Println(rr)
 	fmt.Println(response)
@@ -20,6 +22,7 @@ func TestGetGitignore(t *testing.T) {
 }
 
 func TestGetGitignoreRaw(t *testing.T) {
+	skipOffline(t)
 	rr, response, err := client.Gitignores.GetRaw(ctx, "C")
 	fmt.Println(rr)
 	fmt.Println(response)
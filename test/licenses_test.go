@@ -6,6 +6,7 @@ import (
 )
 
 func TestListlicenses(t *testing.T) {
+	skipOffline(t)
 	rr, response, err := client.Licenses.List(ctx)
 	fmt.Println(rr)
 	fmt.Println(response)
@@ -13,6 +14,7 @@ func TestListlicenses(t *testing.T) {
 }
 
 func TestGetLicense(t *testing.T) {
+	skipOffline(t)
 	rr, response, err := client.Licenses.Get(ctx, "Apache-2.0")
 	fmt.Println(rr)
 	fmt.Println(response)
@@ -20,6 +22,7 @@ func TestGetLicense(t *testing.T) {
 }
 
 func TestGetLicenseRaw(t *testing.T) {
+	skipOffline(t)
 	rr, response, err := client.Licenses.GetRaw(ctx, "Apache-2.0")
 	fmt.Println(rr)
 	fmt.Println(response)
@@ -7,6 +7,7 @@ import (
 )
 
 func TestListEmojis(t *testing.T) {
+	skipOffline(t)
 	rr, response, err := client.Miscellaneous.ListEmojis(ctx)
 	fmt.Println(rr)
 	fmt.Println(response)
@@ -14,6 +15,7 @@ func TestListEmojis(t *testing.T) {
 }
 
 func TestMarkdown(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.MarkdownRequest{
 		Text: gitee.String("# xxxxxxxxxxxx test"),
 	}
@@ -24,6 +26,7 @@ func TestMarkdown(t *testing.T) {
 }
 
 func TestGetEmail(t *testing.T) {
+	skipOffline(t)
 	rr, response, err := client.Miscellaneous.GetEmail(ctx, nil)
 	fmt.Println(*rr.Email)
 	fmt.Println(response)
@@ -0,0 +1,135 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+
+	"github.com/mamh-mixed/go-gitee/oauth"
+)
+
+func TestPasswordTokenSource(t *testing.T) {
+	var grants []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		grant := r.Form.Get("grant_type")
+		grants = append(grants, grant)
+		if r.Form.Get("client_id") != "id" || r.Form.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if grant == "refresh_token" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		if grant != "password" || r.Form.Get("username") != "mamh" || r.Form.Get("scope") != "user_info projects" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// expires_in 为负数, 下一次取 token 的时候就要刷新
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "token", "refresh_token": "refresh", "token_type": "bearer", "expires_in": -1,
+		})
+	}))
+	defer server.Close()
+
+	cfg := oauth.NewConfig("id", "secret", "", oauth.ScopeUserInfo, oauth.ScopeProjects)
+	cfg.Endpoint.TokenURL = server.URL + "/oauth/token"
+	ts := oauth.PasswordTokenSource(ctx, cfg, "mamh", "password")
+
+	for i := 0; i < 2; i++ {
+		tok, err := ts.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.AccessToken != "token" {
+			t.Errorf("Token returned %v, want token", tok.AccessToken)
+		}
+	}
+	if want := []string{"password", "refresh_token", "password"}; len(grants) != 3 || grants[1] != want[1] || grants[2] != want[2] {
+		t.Errorf("grants = %v, want %v", grants, want)
+	}
+}
+
+func TestCheckScopes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v5/user":
+			w.Write([]byte(`{"login":"mamh"}`))
+		case "/api/v5/user/repos", "/api/v5/user/orgs":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Forbidden"}`))
+		}
+	}))
+	defer server.Close()
+	c := gitee.NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/api/v5/")
+
+	r, err := oauth.Check(ctx, c, oauth.OpCreateRepo, oauth.OpManageHooks, oauth.OpEditOrgMembers, oauth.OpReadEmails)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OK() || len(r.Missing) != 1 || r.Missing[0] != oauth.ScopeEmails {
+		t.Errorf("Check returned missing %v, want [emails]", r.Missing)
+	}
+	if len(r.Unverified) != 1 || r.Unverified[0] != oauth.ScopeHook {
+		t.Errorf("Check returned unverified %v, want [hook]", r.Unverified)
+	}
+
+	// 私有部署没有的接口不能让检查失败
+	server404 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v5/user/enterprises":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"404 Not Found"}`))
+		case "/api/v5/gists":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"message":"Bad Gateway"}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server404.Close()
+	c.BaseURL, _ = url.Parse(server404.URL + "/api/v5/")
+	granted, unverified, err := oauth.TokenScopes(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(granted) != 6 || len(unverified) != 5 || unverified[0] != oauth.ScopeGists || unverified[1] != oauth.ScopeEnterprises {
+		t.Errorf("TokenScopes returned granted %v, unverified %v", granted, unverified)
+	}
+
+	r = oauth.CheckScopes([]oauth.Scope{oauth.ScopeUserInfo, oauth.ScopeProjects}, oauth.OpEditRepo)
+	if !r.OK() {
+		t.Errorf("CheckScopes returned missing %v, want none", r.Missing)
+	}
+}
+
+func TestNewEndpoint(t *testing.T) {
+	c, _ := gitee.NewEnterpriseClient("https://gitee.example.com/api/v5/", nil)
+	if e := oauth.NewEndpoint(c.WebURL); e.TokenURL != "https://gitee.example.com/oauth/token" || e.AuthURL != "https://gitee.example.com/oauth/authorize" {
+		t.Errorf("NewEndpoint returned %+v", e)
+	}
+}
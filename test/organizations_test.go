@@ -7,6 +7,7 @@ import (
 )
 
 func TestListOrganizations1(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.OrganizationListOptions{
 		Admin: gitee.Bool(false),
 	}
@@ -19,6 +20,7 @@ func TestListOrganizations1(t *testing.T) {
 }
 
 func TestListOrgMemberships(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.MembershipListOptions{
 		Active: gitee.Bool(false),
 		ListOptions: gitee.ListOptions{
@@ -34,6 +36,7 @@ func TestListOrgMemberships(t *testing.T) {
 }
 
 func TestGetOrgMembership(t *testing.T) {
+	skipOffline(t)
 	user := ""
 	org := "mamh-mixed"
 	member, response, err := client.Organizations.GetOrgMembership(ctx, user, org)
@@ -50,3 +53,24 @@ func TestGetOrgMembership(t *testing.T) {
 	fmt.Println(response2)
 	fmt.Println(err2)
 }
+
+func TestGetOrganization(t *testing.T) {
+	skipOffline(t)
+	org, response, err := client.Organizations.Get(ctx, "mamh-mixed")
+
+	fmt.Println(org)
+	fmt.Println(response)
+	fmt.Println(err)
+}
+
+func TestListOrgMembers(t *testing.T) {
+	skipOffline(t)
+	opts := &gitee.ListMembersOptions{
+		Role: "admin",
+	}
+	members, response, err := client.Organizations.ListMembers(ctx, "mamh-mixed", opts)
+
+	fmt.Println(members)
+	fmt.Println(response)
+	fmt.Println(err)
+}
@@ -0,0 +1,71 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mamh-mixed/go-gitee/gitee"
+	"github.com/mamh-mixed/go-gitee/recorder"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "gitee-session-n=s3cret-session; path=/")
+		w.Header().Set("Link", `<https://gitee.com/api/v5/users/mamh?access_token=s3cret-token&page=2>; rel="next"`)
+		w.Write([]byte(`{"login":"mamh","access_token":"s3cret-token"}`))
+	}))
+
+	newClient := func(mode recorder.Mode) *gitee.Client {
+		rec, err := recorder.New(path, mode, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tp := &gitee.TokenTransport{Token: "s3cret-token", Placement: gitee.TokenInQuery, Transport: rec}
+		c := gitee.NewClient(tp.Client())
+		c.BaseURL, _ = url.Parse(server.URL + "/api/v5/")
+		return c
+	}
+
+	c := newClient(recorder.ModeRecord)
+	if _, _, err := c.Users.Get(ctx, "mamh"); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	b, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(b), "s3cret") || !strings.Contains(string(b), recorder.Redacted) || !strings.Contains(string(b), "page=2") {
+		t.Errorf("cassette contains the token or nothing was redacted:\n%s", b)
+	}
+
+	// 服务器已经关掉了, 只能从 cassette 回放
+	c = newClient(recorder.ModeReplay)
+	for i := 0; i < 2; i++ {
+		user, _, err := c.Users.Get(ctx, "mamh")
+		if err != nil || *user.Login != "mamh" {
+			t.Fatalf("replayed Get returned %v, %v", user, err)
+		}
+	}
+	if _, _, err := c.Users.Get(ctx, "other"); err == nil || !strings.Contains(err.Error(), recorder.ErrNoInteraction.Error()) {
+		t.Errorf("replayed Get of unrecorded user returned %v, want ErrNoInteraction", err)
+	}
+}
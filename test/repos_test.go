@@ -21,6 +21,7 @@ import (
 )
 
 func TestListBranches(t *testing.T) {
+	skipOffline(t)
 	branches, response, err := client.Repositories.ListBranches(ctx, "mamh-java", "jenkins-jenkins")
 	for index, br := range branches {
 		fmt.Println(index, *br.Name, *br.Protected, *br.ProtectionURL)
@@ -30,6 +31,7 @@ func TestListBranches(t *testing.T) {
 }
 
 func TestCreateBranch(t *testing.T) {
+	skipOffline(t)
 	rreq := &gitee.BranchRequest{
 		Refs:       gitee.String("main"), // 从已有分支 创建新的分支
 		BranchName: gitee.String("master"),
@@ -41,6 +43,7 @@ func TestCreateBranch(t *testing.T) {
 }
 
 func TestCreateBranch1(t *testing.T) {
+	skipOffline(t)
 	rreq := &gitee.BranchRequest{
 		Refs:       gitee.String("7a15f560525e17bc2b58e0b6c4bff6ba82e7a557"), // 从一个 commit id 创建新的分支
 		BranchName: gitee.String("master"),
@@ -52,6 +55,7 @@ func TestCreateBranch1(t *testing.T) {
 }
 
 func TestGetBranch(t *testing.T) {
+	skipOffline(t)
 	branch, response, err := client.Repositories.GetBranch(ctx, "mamh-mixed", "go-gitee", "main")
 	fmt.Println(branch)
 	fmt.Println(response)
@@ -59,6 +63,7 @@ func TestGetBranch(t *testing.T) {
 }
 
 func TestGetCommit(t *testing.T) {
+	skipOffline(t)
 	commit, response, err := client.Repositories.GetCommit(ctx, "mamh-mixed", "go-gitee", "8896821c53eda6698ef5c75ba5182e547e8476f1")
 
 	fmt.Println(commit, response, err)
@@ -66,6 +71,7 @@ func TestGetCommit(t *testing.T) {
 }
 
 func TestListCommits(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.CommitsListOptions{}
 	for {
 		commits, response, err := client.Repositories.ListCommits(ctx, "mamh-mixed", "go-gitee", opts)
@@ -85,6 +91,7 @@ func TestListCommits(t *testing.T) {
 }
 
 func TestListComments(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.CommentsListOptions{
 		Order: "desc",
 	}
@@ -110,6 +117,7 @@ func TestListComments(t *testing.T) {
 }
 
 func TestListCommitComments(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.ListOptions{}
 	ref := "c764302e6da151e08608c08ab30e986b04b9064b"
 	for {
@@ -134,6 +142,7 @@ func TestListCommitComments(t *testing.T) {
 }
 
 func TestGetComment(t *testing.T) {
+	skipOffline(t)
 	comment, response, err := client.Repositories.GetComment(ctx, "mamh-mixed", "go-gitee", 14339904)
 	if err != nil {
 		fmt.Println(err)
@@ -148,6 +157,7 @@ func TestGetComment(t *testing.T) {
 }
 
 func TestDeleteComment(t *testing.T) {
+	skipOffline(t)
 	response, err := client.Repositories.DeleteComment(ctx, "mamh-mixed", "go-gitee", 14339904)
 	if err != nil {
 		fmt.Println(err)
@@ -157,6 +167,7 @@ func TestDeleteComment(t *testing.T) {
 }
 
 func TestCreateComment(t *testing.T) {
+	skipOffline(t)
 	creq := &gitee.CommentRequest{
 		Body:     gitee.String("body for comment test/repos_test.go, 4 hang"),
 		Path:     gitee.String("test/repos_test.go"),
@@ -179,6 +190,7 @@ func TestCreateComment(t *testing.T) {
 }
 
 func TestUpdateComment(t *testing.T) {
+	skipOffline(t)
 	creq := &gitee.CommentRequest{
 		Body: gitee.String("update for id 14340395 comment, \n 0 3 14340395 系统提示 2022-11-12 19:17:47 +0800 CST body for comment test/repos_test.go, 18 hang\n"),
 	}
@@ -199,6 +211,7 @@ func TestUpdateComment(t *testing.T) {
 }
 
 func TestCreateKey(t *testing.T) {
+	skipOffline(t)
 	kreq := &gitee.KeyCreateRequest{
 		Key:   gitee.String("ssh-rsa"),
 		Title: gitee.String("public key title"),
@@ -214,6 +227,7 @@ func TestCreateKey(t *testing.T) {
 }
 
 func TestListKeys(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.ListOptions{}
 	keys, response, err := client.Repositories.ListKeys(ctx, "mamh-mixed", "go-gitee", opts)
 	if err != nil {
@@ -226,6 +240,7 @@ func TestListKeys(t *testing.T) {
 }
 
 func TestListAvailableKeys(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.ListOptions{}
 	keys, response, err := client.Repositories.ListAvailableKeys(ctx, "mamh-mixed", "go-gitee", opts)
 	if err != nil {
@@ -238,6 +253,7 @@ func TestListAvailableKeys(t *testing.T) {
 }
 
 func TestEnableKey(t *testing.T) {
+	skipOffline(t)
 	id := int64(3585098)
 	response, err := client.Repositories.EnableKey(ctx, "mamh-mixed", "go-gitee", id)
 	if err != nil {
@@ -248,6 +264,7 @@ func TestEnableKey(t *testing.T) {
 }
 
 func TestDisableKey(t *testing.T) {
+	skipOffline(t)
 	id := int64(3585098)
 	response, err := client.Repositories.DisableKey(ctx, "mamh-mixed", "go-gitee", id)
 	if err != nil {
@@ -258,6 +275,7 @@ func TestDisableKey(t *testing.T) {
 }
 
 func TestGetKey(t *testing.T) {
+	skipOffline(t)
 	id := int64(3584973)
 	key, response, err := client.Repositories.GetKey(ctx, "mamh-mixed", "go-gitee", id)
 	if err != nil {
@@ -270,6 +288,7 @@ func TestGetKey(t *testing.T) {
 }
 
 func TestDeleteKey(t *testing.T) {
+	skipOffline(t)
 	id := int64(3584973)
 	response, err := client.Repositories.DeleteKey(ctx, "mamh-mixed", "go-gitee", id)
 	if err != nil {
@@ -280,6 +299,7 @@ func TestDeleteKey(t *testing.T) {
 }
 
 func TestGetReadme(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryContentGetOptions{
 		Ref: "main", // 分支、tag或commit。默认: 仓库的默认分支(通常是master)
 	}
@@ -294,6 +314,7 @@ func TestGetReadme(t *testing.T) {
 }
 
 func TestGetContents1(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryContentGetOptions{
 		Ref: "main", // 分支、tag或commit。默认: 仓库的默认分支(通常是master)
 	}
@@ -310,6 +331,7 @@ func TestGetContents1(t *testing.T) {
 }
 
 func TestGetContents2(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryContentGetOptions{
 		Ref: "main", // 分支、tag或commit。默认: 仓库的默认分支(通常是master)
 	}
@@ -326,6 +348,7 @@ func TestGetContents2(t *testing.T) {
 }
 
 func TestCreateFile(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc-test"
 	path := "go.mod"
@@ -342,6 +365,7 @@ func TestCreateFile(t *testing.T) {
 }
 
 func TestUpdateFile(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc-test"
 	path := "go.mod"
@@ -359,6 +383,7 @@ func TestUpdateFile(t *testing.T) {
 }
 
 func TestDeleteFile(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc-test"
 	path := "go.mod"
@@ -375,6 +400,7 @@ func TestDeleteFile(t *testing.T) {
 }
 
 func TestList(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryListOptions{}
 	repository, response, err := client.Repositories.List(ctx, "", opts)
 
@@ -395,6 +421,7 @@ func TestList(t *testing.T) {
 }
 
 func TestList1(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryListOptions{}
 	repository, response, err := client.Repositories.List(ctx, "elunez", opts)
 
@@ -406,6 +433,7 @@ func TestList1(t *testing.T) {
 }
 
 func TestListOrganizations(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryListOptions{}
 	repository, response, err := client.Repositories.ListOrganizations(ctx, "mamh-mixed", opts)
 
@@ -416,6 +444,7 @@ func TestListOrganizations(t *testing.T) {
 }
 
 func TestListEnterprises(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryListOptions{}
 	repository, response, err := client.Repositories.ListEnterprises(ctx, "magesfc", opts)
 
@@ -426,6 +455,7 @@ func TestListEnterprises(t *testing.T) {
 }
 
 func TestCompareCommits(t *testing.T) {
+	skipOffline(t)
 	owner := "mamh-mixed"
 	repo := "go-gitee"
 	base := "7a15f560525e17bc2b58e0b6c4bff6ba82e7a557"
@@ -440,6 +470,7 @@ func TestCompareCommits(t *testing.T) {
 }
 
 func TestCreate(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryCreateRequest{
 		Name:        gitee.String("repo_name2"), // 仓库名称
 		Path:        gitee.String("repo_Path2"), //路径 (请注意：仓库路径即仓库访问 URL 地址，更改仓库路径将导致原克隆地址不可用)
@@ -453,6 +484,7 @@ func TestCreate(t *testing.T) {
 }
 
 func TestCreateOrgRepository(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryCreateOrgRequest{
 		RepositoryCreateRequest: &gitee.RepositoryCreateRequest{
 			Name:        gitee.String("repo_name1"), // 仓库名称
@@ -468,6 +500,7 @@ func TestCreateOrgRepository(t *testing.T) {
 }
 
 func TestCreateEntRepository(t *testing.T) {
+	skipOffline(t)
 	opts := &gitee.RepositoryCreateEntRequest{
 		RepositoryCreateRequest: &gitee.RepositoryCreateRequest{
 			Name:        gitee.String("magesfc仓库名称"), // 仓库名称
@@ -491,6 +524,7 @@ func TestCreateEntRepository(t *testing.T) {
 }
 
 func TestUpdateBranchProtection(t *testing.T) {
+	skipOffline(t)
 	owner := "mamh-mixed"
 	repo := "go-gitee"
 	branch := "main"
@@ -507,6 +541,7 @@ func TestUpdateBranchProtection(t *testing.T) {
 }
 
 func TestRemoveBranchProtection(t *testing.T) {
+	skipOffline(t)
 	owner := "mamh-mixed"
 	repo := "go-gitee"
 	branch := "main"
@@ -521,6 +556,7 @@ func TestRemoveBranchProtection(t *testing.T) {
 }
 
 func TestUpdateBranchWildcardProtection(t *testing.T) {
+	skipOffline(t)
 	owner := "mamh-mixed"
 	repo := "go-gitee"
 	wildcard := "main_wildcard"
@@ -540,6 +576,7 @@ func TestUpdateBranchWildcardProtection(t *testing.T) {
 }
 
 func TestCreateBranchWildcardProtection(t *testing.T) {
+	skipOffline(t)
 	owner := "mamh-mixed"
 	repo := "go-gitee"
 
@@ -559,6 +596,7 @@ func TestCreateBranchWildcardProtection(t *testing.T) {
 }
 
 func TestRemoveBranchWildcardProtection(t *testing.T) {
+	skipOffline(t)
 	owner := "mamh-mixed"
 	repo := "go-gitee"
 	branch := "main"
@@ -573,6 +611,7 @@ func TestRemoveBranchWildcardProtection(t *testing.T) {
 }
 
 func TestGetPagesInfo(t *testing.T) {
+	skipOffline(t)
 	owner := "oschina"
 	repo := "git-osc"
 
@@ -586,6 +625,7 @@ func TestGetPagesInfo(t *testing.T) {
 }
 
 func TestGetRepository(t *testing.T) {
+	skipOffline(t)
 	owner := "oschina"
 	repo := "git-osc"
 
@@ -598,6 +638,7 @@ func TestGetRepository(t *testing.T) {
 }
 
 func TestEditRepository(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc-test"
 
@@ -614,6 +655,7 @@ func TestEditRepository(t *testing.T) {
 }
 
 func TestDeletetRepository(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "sample_repository"
 
@@ -623,6 +665,7 @@ func TestDeletetRepository(t *testing.T) {
 }
 
 func TestGetPushConfig(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc"
 	pushConfig, response, err := client.Repositories.GetPushConfig(ctx, owner, repo)
@@ -634,6 +677,7 @@ func TestGetPushConfig(t *testing.T) {
 }
 
 func TestUpdatePushConfig(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc"
 	pushConfig, response, err := client.Repositories.GetPushConfig(ctx, owner, repo)
@@ -656,6 +700,7 @@ func TestUpdatePushConfig(t *testing.T) {
 }
 
 func TestListContributors(t *testing.T) {
+	skipOffline(t)
 	owner := "log4j"
 	repo := "pig"
 	opts := &gitee.ContributorListOptions{}
@@ -668,6 +713,7 @@ func TestListContributors(t *testing.T) {
 }
 
 func TestListTags(t *testing.T) {
+	skipOffline(t)
 	owner := "log4j"
 	repo := "pig"
 	opts := &gitee.ListOptions{}
@@ -680,6 +726,7 @@ func TestListTags(t *testing.T) {
 }
 
 func TestCreateTag(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc"
 	ctq := &gitee.RepositoryTagCreateRequest{
@@ -696,6 +743,7 @@ func TestCreateTag(t *testing.T) {
 }
 
 func TestClear(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc"
 	response, err := client.Repositories.Clear(ctx, owner, repo)
@@ -705,6 +753,7 @@ func TestClear(t *testing.T) {
 }
 
 func TestListCollaborators(t *testing.T) {
+	skipOffline(t)
 	owner := "log4j"
 	repo := "pig"
 	opts := &gitee.ListOptions{}
@@ -717,6 +766,7 @@ func TestListCollaborators(t *testing.T) {
 }
 
 func TestIsCollaborator(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc"
 	me := "mamh"
@@ -728,6 +778,7 @@ func TestIsCollaborator(t *testing.T) {
 }
 
 func TestAddCollaborator(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc"
 	me := "mamh"
@@ -742,6 +793,7 @@ func TestAddCollaborator(t *testing.T) {
 }
 
 func TestRemoveCollaborator(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc"
 	me := "mamh"
@@ -753,6 +805,7 @@ func TestRemoveCollaborator(t *testing.T) {
 }
 
 func TestGetPermissionLevel(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc"
 	me := "mamh"
@@ -764,6 +817,7 @@ func TestGetPermissionLevel(t *testing.T) {
 }
 
 func TestListForks(t *testing.T) {
+	skipOffline(t)
 	owner := "y_project"
 	repo := "RuoYi"
 	opts := &gitee.RepositoryListForksOptions{}
@@ -774,6 +828,7 @@ func TestListForks(t *testing.T) {
 }
 
 func TestCreateFork(t *testing.T) {
+	skipOffline(t)
 	owner := "y_project"
 	repo := "RuoYi"
 	opts := &gitee.RepositoryCreateForkOptions{
@@ -788,6 +843,7 @@ func TestCreateFork(t *testing.T) {
 }
 
 func TestListTraffic(t *testing.T) {
+	skipOffline(t)
 	owner := "mamh-mixed"
 	repo := "go-gitee"
 	opts := &gitee.TrafficDataRequest{
@@ -801,6 +857,7 @@ func TestListTraffic(t *testing.T) {
 }
 
 func TestListReleases(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "ruoyi_git"
 	opts := &gitee.RepositoryReleaseListOptions{}
@@ -811,6 +868,7 @@ func TestListReleases(t *testing.T) {
 }
 
 func TestCreateRelease(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "ruoyi_git"
 	releaseReq := &gitee.RepositoryReleaseCreateRequest{
@@ -829,6 +887,7 @@ func TestCreateRelease(t *testing.T) {
 }
 
 func TestGetRelease(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "ruoyi_git"
 	id := int64(264806)
@@ -839,6 +898,7 @@ func TestGetRelease(t *testing.T) {
 }
 
 func TestEditRelease(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "ruoyi_git"
 	id := int64(264863)
@@ -857,6 +917,7 @@ func TestEditRelease(t *testing.T) {
 }
 
 func TestDeleteRelease(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "ruoyi_git"
 	id := int64(264806)
@@ -867,6 +928,7 @@ func TestDeleteRelease(t *testing.T) {
 }
 
 func TestGetLatestRelease(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "ruoyi_git"
 	rr, response, err := client.Repositories.GetLatestRelease(ctx, owner, repo)
@@ -876,6 +938,7 @@ func TestGetLatestRelease(t *testing.T) {
 }
 
 func TestGetReleaseByTag(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "ruoyi_git"
 	tag := "v10.10.10"
@@ -886,6 +949,7 @@ func TestGetReleaseByTag(t *testing.T) {
 }
 
 func TestCreateOpenGo(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "ruoyi_git"
 
@@ -895,6 +959,7 @@ func TestCreateOpenGo(t *testing.T) {
 }
 
 func TestListHooks(t *testing.T) {
+	skipOffline(t)
 	owner := "magesfc"
 	repo := "magesfc"
 	opts := &gitee.ListOptions{}
@@ -903,3 +968,31 @@ func TestListHooks(t *testing.T) {
 	fmt.Println(response)
 	fmt.Println(err)
 }
+
+func TestCreateHook(t *testing.T) {
+	skipOffline(t)
+	owner := "mamh-mixed"
+	repo := "go-gitee"
+	hreq := &gitee.HookRequest{
+		URL:            gitee.String("https://example.com/gitee/hook"),
+		EncryptionType: gitee.Int(gitee.HookEncryptionSign),
+		Password:       gitee.String("secret"),
+		PushEvents:     gitee.Bool(true),
+		NoteEvents:     gitee.Bool(true),
+	}
+	hook, response, err := client.Repositories.CreateHook(ctx, owner, repo, hreq)
+	fmt.Println(hook)
+	fmt.Println(response)
+	fmt.Println(err)
+	if err != nil {
+		return
+	}
+
+	response, err = client.Repositories.TestHook(ctx, owner, repo, *hook.ID)
+	fmt.Println(response)
+	fmt.Println(err)
+
+	response, err = client.Repositories.DeleteHook(ctx, owner, repo, *hook.ID)
+	fmt.Println(response)
+	fmt.Println(err)
+}
@@ -21,6 +21,7 @@ import (
 )
 
 func TestGetUser(t *testing.T) {
+	skipOffline(t)
 	user, response, err := client.Users.Get(ctx, "")
 	fmt.Println(user)
 	fmt.Println(response)
@@ -28,6 +29,7 @@ func TestGetUser(t *testing.T) {
 }
 
 func TestListSshKeys(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.ListOptions{
 		Page:    1,  // 这个key目前只有2个，这里一页就能获取全部的了
 		PerPage: 10, // perPage 表示每页的总数
@@ -49,6 +51,7 @@ func TestListSshKeys(t *testing.T) {
 }
 
 func TestGetSshKey(t *testing.T) {
+	skipOffline(t)
 	keys, response, err := client.Users.GetKey(ctx, 3544397)
 	fmt.Println(keys)
 	fmt.Println(response)
@@ -56,6 +59,7 @@ func TestGetSshKey(t *testing.T) {
 }
 
 func TestListFollowers(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.ListOptions{
 		Page:    90,  // page 表示从第几页 开始，一般从第 1 页开始，然后第 2 页，然后第 3 页，到最后一页
 		PerPage: 100, // perPage 表示每页的总数
@@ -79,6 +83,7 @@ func TestListFollowers(t *testing.T) {
 }
 
 func TestListFollowers1(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.ListOptions{
 		Page:    1,
 		PerPage: 10,
@@ -90,6 +95,7 @@ func TestListFollowers1(t *testing.T) {
 }
 
 func TestListFollowings(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.ListOptions{
 		Page:    1,
 		PerPage: 10,
@@ -102,6 +108,7 @@ func TestListFollowings(t *testing.T) {
 }
 
 func TestGetUserFollowings1(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.ListOptions{
 		Page:    1,
 		PerPage: 10,
@@ -114,6 +121,7 @@ func TestGetUserFollowings1(t *testing.T) {
 }
 
 func TestListNamespaces(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.NamespacesOptions{
 		Mode: "project",
 	}
@@ -124,6 +132,7 @@ func TestListNamespaces(t *testing.T) {
 }
 
 func TestGetNamespace(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.NamespaceOptions{
 		Path: "mamh-java",
 	}
@@ -134,6 +143,7 @@ func TestGetNamespace(t *testing.T) {
 }
 
 func TestGetNamespace1(t *testing.T) {
+	skipOffline(t)
 	var opts = &gitee.NamespaceOptions{
 		Path: "mamh",
 	}
@@ -144,6 +154,7 @@ func TestGetNamespace1(t *testing.T) {
 }
 
 func TestIsFollowing(t *testing.T) {
+	skipOffline(t)
 	user := "mamh" // under mamh the following list there is y_project
 	target := "y_project"
 	b, response, err := client.Users.IsFollowing(ctx, user, target)
@@ -153,6 +164,7 @@ func TestIsFollowing(t *testing.T) {
 }
 
 func TestFollow(t *testing.T) {
+	skipOffline(t)
 	user := "y_project"
 	response, err := client.Users.Follow(ctx, user)
 	fmt.Println(response)
@@ -160,6 +172,7 @@ func TestFollow(t *testing.T) {
 }
 
 func TestUnfollow(t *testing.T) {
+	skipOffline(t)
 	user := "y_project"
 	response, err := client.Users.Unfollow(ctx, user)
 	fmt.Println(response)
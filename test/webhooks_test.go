@@ -0,0 +1,361 @@
+//Copyright magesfc bright.ma
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+package test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/mamh-mixed/go-gitee/gitee"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWebHook(t *testing.T) {
+	payload := []byte(`{
+		"hook_name": "push_hooks",
+		"hook_id": 1,
+		"timestamp": "1576754827988",
+		"ref": "refs/heads/master",
+		"before": "0000000000000000000000000000000000000000",
+		"after": "8896821c53eda6698ef5c75ba5182e547e8476f1",
+		"total_commits_count": 1,
+		"commits": [{"id": "8896821c53eda6698ef5c75ba5182e547e8476f1", "message": "init",
+			"author": {"name": "mamh", "email": "mamh@example.com", "username": "mamh"}}],
+		"repository": {"id": 1, "full_name": "mamh-mixed/go-gitee", "namespace": "mamh-mixed"},
+		"pusher": {"name": "mamh", "username": "mamh"}
+	}`)
+
+	event, err := gitee.ParseWebHook(gitee.PushHookEventType, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	push, ok := event.(*gitee.PushHookEvent)
+	if !ok {
+		t.Fatalf("ParseWebHook returned %T, want *gitee.PushHookEvent", event)
+	}
+	if *push.HookName != "push_hooks" || *push.Repository.FullName != "mamh-mixed/go-gitee" || len(push.Commits) != 1 {
+		t.Errorf("ParseWebHook returned %v", push)
+	}
+	if push.Timestamp.UnixNano()/1e6 != 1576754827988 {
+		t.Errorf("ParseWebHook timestamp = %v", push.Timestamp)
+	}
+
+	if _, err := gitee.ParseWebHook("Unknown Hook", payload); err == nil {
+		t.Error("ParseWebHook with unknown event type returned no error")
+	}
+}
+
+func TestHookHasEvent(t *testing.T) {
+	hook := &gitee.Hook{
+		PushEvents: gitee.Bool(true),
+		NoteEvents: gitee.Bool(false),
+	}
+	if !hook.HasEvent(gitee.PushHookEventType) {
+		t.Error("HasEvent(Push Hook) = false, want true")
+	}
+	if hook.HasEvent(gitee.NoteHookEventType) || hook.HasEvent(gitee.IssueHookEventType) {
+		t.Error("HasEvent returned true for a disabled event")
+	}
+}
+
+func signHook(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidatePayload(t *testing.T) {
+	secret := "secret"
+	now := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
+	stale := strconv.FormatInt(time.Now().Add(-2*time.Hour).UnixNano()/1e6, 10)
+
+	tests := []struct {
+		name      string
+		token     string
+		timestamp string
+		wantErr   error
+	}{
+		{"password", secret, now, nil},
+		{"signature", signHook(now, secret), now, nil},
+		{"url encoded signature", url.QueryEscape(signHook(now, secret)), now, nil},
+		{"wrong password", "wrong", now, gitee.ErrInvalidHookToken},
+		{"missing token", "", now, gitee.ErrInvalidHookToken},
+		{"stale signature", signHook(stale, secret), stale, gitee.ErrStaleHookTimestamp},
+		{"missing timestamp", signHook(now, secret), "", gitee.ErrStaleHookTimestamp},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("POST", "http://localhost/hook", strings.NewReader(`{"hook_name":"push_hooks"}`))
+		req.Header.Set(gitee.EventTypeHeader, gitee.PushHookEventType)
+		req.Header.Set(gitee.TokenHeader, tt.token)
+		req.Header.Set(gitee.TimestampHeader, tt.timestamp)
+
+		payload, err := gitee.ValidatePayload(req, []byte(secret))
+		if err != tt.wantErr {
+			t.Errorf("%s: ValidatePayload returned error %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if err == nil && string(payload) != `{"hook_name":"push_hooks"}` {
+			t.Errorf("%s: ValidatePayload returned payload %q", tt.name, payload)
+		}
+	}
+}
+
+func TestHookHandler(t *testing.T) {
+	secret := "secret"
+	handler := gitee.NewHookHandler([]byte(secret))
+	var logged strings.Builder
+	handler.ErrorLog = log.New(&logged, "", 0)
+
+	var pushes, middleware int
+	handler.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			middleware++
+			next.ServeHTTP(w, r)
+		})
+	})
+	handler.OnPush(func(ctx context.Context, event *gitee.PushHookEvent) error {
+		pushes++
+		return nil
+	})
+	handler.OnNote(func(ctx context.Context, event *gitee.NoteHookEvent) error {
+		return errors.New("boom")
+	})
+
+	send := func(method, eventType, token, body string) int {
+		req := httptest.NewRequest(method, "/hook", strings.NewReader(body))
+		req.Header.Set(gitee.EventTypeHeader, eventType)
+		req.Header.Set(gitee.TokenHeader, token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		eventType  string
+		token      string
+		body       string
+		wantStatus int
+	}{
+		{"push", "POST", gitee.PushHookEventType, secret, `{"ref":"refs/heads/master"}`, http.StatusOK},
+		{"duplicate push", "POST", gitee.PushHookEventType, secret, `{"ref":"refs/heads/master"}`, http.StatusOK},
+		{"bad token", "POST", gitee.PushHookEventType, "wrong", `{}`, http.StatusUnauthorized},
+		{"unknown event", "POST", "Unknown Hook", secret, `{}`, http.StatusBadRequest},
+		{"bad json", "POST", gitee.PushHookEventType, secret, `{`, http.StatusBadRequest},
+		{"no callback", "POST", gitee.IssueHookEventType, secret, `{}`, http.StatusNoContent},
+		{"callback error", "POST", gitee.NoteHookEventType, secret, `{}`, http.StatusInternalServerError},
+		{"not post", "GET", gitee.PushHookEventType, secret, ``, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if got := send(tt.method, tt.eventType, tt.token, tt.body); got != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, got, tt.wantStatus)
+		}
+	}
+
+	if pushes != 1 {
+		t.Errorf("push callback called %d times, want 1", pushes)
+	}
+	if middleware != len(tests) {
+		t.Errorf("middleware called %d times, want %d", middleware, len(tests))
+	}
+	if !strings.Contains(logged.String(), "boom") {
+		t.Errorf("ErrorLog = %q, want the callback error", logged.String())
+	}
+
+	// 回调的错误不能返回给发送方
+	req := httptest.NewRequest("POST", "/hook", strings.NewReader(`{}`))
+	req.Header.Set(gitee.EventTypeHeader, gitee.NoteHookEventType)
+	req.Header.Set(gitee.TokenHeader, secret)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if strings.Contains(rec.Body.String(), "boom") {
+		t.Errorf("response body %q contains the callback error", rec.Body)
+	}
+}
+
+func TestHookHandlerDedup(t *testing.T) {
+	handler := gitee.NewHookHandler(nil)
+	handler.ErrorLog = log.New(ioutil.Discard, "", 0)
+
+	var pushes int
+	started, release := make(chan bool), make(chan error)
+	handler.OnPush(func(ctx context.Context, event *gitee.PushHookEvent) error {
+		pushes++
+		if *event.After == "slow" {
+			started <- true
+			return <-release
+		}
+		return nil
+	})
+
+	send := func(after, timestamp string) int {
+		body := `{"after":"` + after + `","timestamp":"` + timestamp + `","sign":"s` + timestamp + `"}`
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(body))
+		req.Header.Set(gitee.EventTypeHeader, gitee.PushHookEventType)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// 重新推送的 timestamp 和 sign 都不一样
+	send("abc", "1576754827988")
+	if code := send("abc", "1576754899999"); code != http.StatusOK || pushes != 1 {
+		t.Errorf("redelivery: status = %d, pushes = %d, want 200 and 1", code, pushes)
+	}
+
+	// 第一次还在处理的时候重复推送, 不能确认
+	done := make(chan int)
+	go func() { done <- send("slow", "1") }()
+	<-started
+	if code := send("slow", "2"); code != http.StatusConflict {
+		t.Errorf("duplicate of a running delivery: status = %d, want 409", code)
+	}
+	release <- errors.New("boom")
+	if code := <-done; code != http.StatusInternalServerError {
+		t.Errorf("failed delivery: status = %d, want 500", code)
+	}
+
+	// 失败之后重新推送还要处理
+	go func() { done <- send("slow", "3") }()
+	<-started
+	release <- nil
+	if code := <-done; code != http.StatusOK || pushes != 3 {
+		t.Errorf("retry: status = %d, pushes = %d, want 200 and 3", code, pushes)
+	}
+}
+
+func TestHookHandlerPanic(t *testing.T) {
+	handler := gitee.NewHookHandler(nil)
+
+	var calls int
+	handler.OnPush(func(ctx context.Context, event *gitee.PushHookEvent) error {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		return nil
+	})
+
+	send := func() (code int, panicked bool) {
+		defer func() {
+			// net/http 也是这样 recover 的
+			if recover() != nil {
+				panicked = true
+			}
+		}()
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(`{"after":"8896"}`))
+		req.Header.Set(gitee.EventTypeHeader, gitee.PushHookEventType)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code, false
+	}
+
+	if _, panicked := send(); !panicked {
+		t.Fatal("callback panic was not propagated")
+	}
+	// panic 的 delivery 没有处理过, 重新推送还要处理
+	if code, _ := send(); code != http.StatusOK || calls != 2 {
+		t.Errorf("redelivery after panic: status = %d, calls = %d, want 200 and 2", code, calls)
+	}
+}
+
+func TestNewHookRequest(t *testing.T) {
+	secret := []byte("secret")
+	handler := gitee.NewHookHandler(secret)
+
+	var got *gitee.IssueHookEvent
+	handler.OnIssue(func(ctx context.Context, event *gitee.IssueHookEvent) error {
+		got = event
+		return nil
+	})
+
+	d := &gitee.HookDelivery{
+		EventType:      gitee.IssueHookEventType,
+		Payload:        []byte(`{"action":"open","issue":{"number":"I5XYZ1"}}`),
+		Secret:         secret,
+		EncryptionType: gitee.HookEncryptionSign,
+	}
+	req, err := gitee.NewHookRequest("http://localhost/hook", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if got == nil || *got.HookName != "issue_hooks" || *got.Issue.Number != "I5XYZ1" || got.Sign == nil {
+		t.Errorf("handler received %v", got)
+	}
+}
+
+func TestNewHookRequestPayload(t *testing.T) {
+	d := &gitee.HookDelivery{
+		EventType:      gitee.PushHookEventType,
+		Payload:        []byte(`{"password":"old", "message":"fix <b> & \u003ci\u003e",` + "\n" + `  "after":"8896"}`),
+		Secret:         []byte("secret"),
+		EncryptionType: gitee.HookEncryptionSign,
+		Timestamp:      time.Unix(1576754827, 988e6),
+	}
+	req, err := gitee.NewHookRequest("http://localhost/hook", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	sign := req.Header.Get(gitee.TokenHeader)
+	want := `{"message":"fix <b> & \u003ci\u003e",` + "\n" + `  "after":"8896","hook_name":"push_hooks","timestamp":"1576754827988","sign":"` + sign + `"}`
+	if string(body) != want {
+		t.Errorf("payload = %s, want %s", body, want)
+	}
+
+	// 已经有的字段就地替换
+	d.Payload = []byte(`{"timestamp":"1","hook_name":"x","sign":"s","b":[1, 2]}`)
+	d.EncryptionType = gitee.HookEncryptionPassword
+	req, _ = gitee.NewHookRequest("http://localhost/hook", d)
+	body, _ = ioutil.ReadAll(req.Body)
+	want = `{"timestamp":"1576754827988","hook_name":"push_hooks","b":[1, 2],"password":"secret"}`
+	if string(body) != want {
+		t.Errorf("payload = %s, want %s", body, want)
+	}
+}
+
+func TestSetPayloadField(t *testing.T) {
+	payload := []byte(`{"ref":"refs/heads/master", "size":1.50,"message":"a <b> & c"}`)
+	got, err := gitee.SetPayloadField(payload, "ref", "refs/heads/dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"ref":"refs/heads/dev", "size":1.50,"message":"a <b> & c"}`; string(got) != want {
+		t.Errorf("SetPayloadField = %s, want %s", got, want)
+	}
+	got, _ = gitee.SetPayloadField(payload, "action", "merge")
+	if want := `{"ref":"refs/heads/master", "size":1.50,"message":"a <b> & c","action":"merge"}`; string(got) != want {
+		t.Errorf("SetPayloadField of a new field = %s, want %s", got, want)
+	}
+	if _, err := gitee.SetPayloadField([]byte(`[1]`), "ref", "x"); err == nil {
+		t.Error("SetPayloadField of an array returned no error")
+	}
+}